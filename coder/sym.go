@@ -5,9 +5,11 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/des"
+	"crypto/rand"
 	"crypto/rc4"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -80,6 +82,47 @@ func AesDecryptCBC(content string, key string) string {
 	return string(decrypted)
 }
 
+// AesEncryptGCM AES-GCM加密，key的长度必须为16、24或者32；返回base64(nonce+密文)
+func AesEncryptGCM(content string, key string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(content), nil)), nil
+}
+
+// AesDecryptGCM AES-GCM解密，content为AesEncryptGCM返回的base64(nonce+密文)
+func AesDecryptGCM(content string, key string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("密文长度不合法")
+	}
+	plainData, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plainData), nil
+}
+
+func newGCM(key string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
 func DESEncryptCBC(content string, key string, iv string) string {
 	block, _ := des.NewCipher([]byte(key))
 	data := pkcs5Padding([]byte(content), block.BlockSize())
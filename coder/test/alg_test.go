@@ -150,3 +150,20 @@ func TestDSA(t *testing.T) {
 //	s1 := coder.AesDecryptECB(content, key)
 //	t.Logf(s1)
 //}
+
+func TestAesGCM(t *testing.T) {
+	content := "abcdefg"
+	key := "simonalong123456"
+	s1, err := coder.AesEncryptGCM(content, key)
+	if err != nil {
+		t.Fatalf("encrypt error: %v", err)
+	}
+	s2, err := coder.AesDecryptGCM(s1, key)
+	if err != nil || s2 != content {
+		t.Fatalf("decrypt error: %v, %s", err, s2)
+	}
+
+	if _, err := coder.AesDecryptGCM(s1, "simonalong654321"); err == nil {
+		t.Fatalf("decrypt with wrong key should fail")
+	}
+}
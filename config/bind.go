@@ -0,0 +1,441 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/simonalong/gole/util"
+)
+
+// ByteSize 字节大小，绑定时候支持：1024、512B、10KB、10MB、1GB（也支持KiB、MiB、GiB的写法），单位不区分大小写
+type ByteSize int64
+
+var durationType = reflect.TypeOf(time.Duration(0))
+var byteSizeType = reflect.TypeOf(ByteSize(0))
+var timeType = reflect.TypeOf(time.Time{})
+
+// 时间支持的格式，没有时区的按照本地时区解析
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1 << 10,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1 << 20,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1 << 30,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1 << 40,
+	"tib": 1 << 40,
+}
+
+// 绑定之后的校验，由validate包注册（validate包依赖了config，这里不能直接引用）
+var bindValidator func(object any, fieldNames ...string) (bool, string, string)
+
+// RegisterBindValidator 注册绑定之后的校验函数，引入validate包时候会自动注册validate.Check；
+// 绑定时候会按照属性逐个校验，fieldNames为待校验的属性名
+func RegisterBindValidator(validator func(object any, fieldNames ...string) (bool, string, string)) {
+	bindValidator = validator
+}
+
+// BindError 绑定配置的异常，包含所有不合法或者缺失的key
+type BindError struct {
+	Prefix string
+	Fields []BindFieldError
+}
+
+// BindFieldError 某个key的绑定异常
+type BindFieldError struct {
+	Key    string
+	ErrMsg string
+}
+
+func (e *BindError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("绑定配置[%s]失败：", e.Prefix))
+	for _, field := range e.Fields {
+		builder.WriteString(fmt.Sprintf("\n  - %s：%s", field.Key, field.ErrMsg))
+	}
+	return builder.String()
+}
+
+// Bind 将前缀prefix下的配置绑定到结构体T上，支持如下标签
+//   - yaml/json：配置的key，没有配置则按照属性名宽松匹配，比如：ConnectTimeout 可匹配 connect-timeout、connectTimeout、connect_timeout
+//   - default：配置不存在时候的默认值，比如：`default:"3s"`，数组用逗号分隔
+//   - required：`required:"true"` 表示配置必须存在
+//   - match：绑定完成后会调用validate.Check逐个校验有该标签的属性，异常的key为属性对应的key；需要引入validate包，没有引入则返回异常
+//
+// 支持的类型：基本类型、time.Duration（3s、500ms，纯数字表示毫秒）、config.ByteSize（10MB）、time.Time、结构体、指针、数组和map；
+// 所有不合法以及缺失的key会汇总到一个BindError中返回
+func Bind[T any](prefix string) (T, error) {
+	return BindFrom[T](defaultConfig, prefix)
+}
+
+// BindFrom 将配置实例c中前缀prefix下的配置绑定到结构体T上，规则同Bind
+func BindFrom[T any](c *Config, prefix string) (T, error) {
+	var result T
+	err := c.BindTo(prefix, &result)
+	return result, err
+}
+
+// BindTo 将前缀prefix下的配置绑定到targetPtrObj上，规则同Bind
+func BindTo(prefix string, targetPtrObj any) error {
+	return defaultConfig.BindTo(prefix, targetPtrObj)
+}
+
+// BindTo 将前缀prefix下的配置绑定到targetPtrObj上，规则同Bind
+func (c *Config) BindTo(prefix string, targetPtrObj any) error {
+	targetValue := reflect.ValueOf(targetPtrObj)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return &BindError{Prefix: prefix, Fields: []BindFieldError{{Key: prefix, ErrMsg: "绑定的对象需要为非空指针"}}}
+	}
+	return bindProperty(c.currentProperty(), prefix, targetValue)
+}
+
+func bindProperty(property *ApplicationProperty, prefix string, targetValue reflect.Value) error {
+	var data any
+	if nil != property {
+		prefix = property.canonicalKey(prefix)
+		data = resolveValueOrRaw(property, prefix, doGetValue(property.ValueDeepMap, prefix))
+	}
+
+	binder := &valueBinder{}
+	binder.bind(prefix, data, targetValue.Elem())
+	binder.validate()
+	if len(binder.errs) > 0 {
+		return &BindError{Prefix: prefix, Fields: binder.errs}
+	}
+	return nil
+}
+
+type valueBinder struct {
+	errs []BindFieldError
+	// 有match标签的属性，绑定完成后校验
+	checks []bindCheck
+}
+
+// 待校验的属性：owner为属性所在的结构体
+type bindCheck struct {
+	key       string
+	owner     reflect.Value
+	fieldName string
+}
+
+func (b *valueBinder) addErr(key, errMsg string) {
+	b.errs = append(b.errs, BindFieldError{Key: key, ErrMsg: errMsg})
+}
+
+// 校验有match标签的属性，绑定失败的属性不再校验
+func (b *valueBinder) validate() {
+	failedKeys := map[string]bool{}
+	for _, fieldErr := range b.errs {
+		failedKeys[fieldErr.Key] = true
+	}
+	for _, check := range b.checks {
+		if failedKeys[check.key] {
+			continue
+		}
+		if bindValidator == nil {
+			b.addErr(check.key, "配置有match校验标签，但是没有引入validate包：import _ \"github.com/simonalong/gole/validate\"")
+			continue
+		}
+		if ok, _, errMsg := bindValidator(check.owner.Interface(), check.fieldName); !ok {
+			b.addErr(check.key, errMsg)
+		}
+	}
+}
+
+func (b *valueBinder) bind(key string, data any, target reflect.Value) {
+	if data == nil {
+		// 没有配置的结构体也需要处理其中属性的默认值
+		if target.Kind() == reflect.Struct {
+			b.bindStruct(key, map[string]any{}, target)
+		}
+		return
+	}
+	if target.Type() == durationType {
+		b.bindDuration(key, data, target)
+		return
+	}
+	if target.Type() == byteSizeType {
+		b.bindByteSize(key, data, target)
+		return
+	}
+	if target.Type() == timeType {
+		b.bindTime(key, data, target)
+		return
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		b.bind(key, data, target.Elem())
+	case reflect.Struct:
+		dataMap, ok := data.(map[string]any)
+		if !ok {
+			b.addErr(key, fmt.Sprintf("值[%v]不是对象类型", data))
+			return
+		}
+		b.bindStruct(key, dataMap, target)
+	case reflect.Map:
+		b.bindMap(key, data, target)
+	case reflect.Slice:
+		b.bindSlice(key, data, target)
+	case reflect.Interface:
+		target.Set(reflect.ValueOf(data))
+	default:
+		b.bindScalar(key, data, target)
+	}
+}
+
+func (b *valueBinder) bindStruct(prefix string, dataMap map[string]any, target reflect.Value) {
+	targetType := target.Type()
+	for index := 0; index < targetType.NumField(); index++ {
+		field := targetType.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline := fieldKeyName(field)
+		if name == "-" {
+			continue
+		}
+		// 匿名结构体以及inline的属性与父结构体在同一层级
+		if inline || (field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct) {
+			b.bindStruct(prefix, dataMap, target.Field(index))
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		key, value, exist := lookupField(dataMap, name)
+		fullKey := joinKey(prefix, key)
+		if isCheckedField(field) {
+			b.checks = append(b.checks, bindCheck{key: fullKey, owner: target, fieldName: field.Name})
+		}
+		if !exist {
+			if defaultValue, hasDefault := field.Tag.Lookup("default"); hasDefault {
+				b.bind(fullKey, defaultValue, target.Field(index))
+				continue
+			}
+			if field.Tag.Get("required") == "true" {
+				b.addErr(fullKey, "配置不存在")
+				continue
+			}
+		}
+		b.bind(fullKey, value, target.Field(index))
+	}
+}
+
+func (b *valueBinder) bindMap(key string, data any, target reflect.Value) {
+	dataMap, ok := data.(map[string]any)
+	if !ok {
+		b.addErr(key, fmt.Sprintf("值[%v]不是map类型", data))
+		return
+	}
+	targetType := target.Type()
+	if targetType.Key().Kind() != reflect.String {
+		b.addErr(key, "map的key只支持string类型")
+		return
+	}
+	result := reflect.MakeMapWithSize(targetType, len(dataMap))
+	for mapKey, mapValue := range dataMap {
+		item := reflect.New(targetType.Elem()).Elem()
+		b.bind(joinKey(key, mapKey), mapValue, item)
+		result.SetMapIndex(reflect.ValueOf(mapKey).Convert(targetType.Key()), item)
+	}
+	target.Set(result)
+}
+
+func (b *valueBinder) bindSlice(key string, data any, target reflect.Value) {
+	var items []any
+	switch v := data.(type) {
+	case []any:
+		items = v
+	case string:
+		// 字符串按照逗号分隔，比如：default:"a,b,c"
+		if strings.TrimSpace(v) != "" {
+			for _, item := range strings.Split(v, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		}
+	default:
+		items = []any{v}
+	}
+
+	result := reflect.MakeSlice(target.Type(), len(items), len(items))
+	for index, item := range items {
+		b.bind(fmt.Sprintf("%s[%d]", key, index), item, result.Index(index))
+	}
+	target.Set(result)
+}
+
+func (b *valueBinder) bindDuration(key string, data any, target reflect.Value) {
+	duration, err := toDuration(data)
+	if err != nil {
+		b.addErr(key, err.Error())
+		return
+	}
+	target.SetInt(int64(duration))
+}
+
+func (b *valueBinder) bindByteSize(key string, data any, target reflect.Value) {
+	size, err := ParseByteSize(util.ToString(data))
+	if err != nil {
+		b.addErr(key, err.Error())
+		return
+	}
+	target.SetInt(size)
+}
+
+func (b *valueBinder) bindTime(key string, data any, target reflect.Value) {
+	value, err := toTime(data)
+	if err != nil {
+		b.addErr(key, err.Error())
+		return
+	}
+	target.Set(reflect.ValueOf(value))
+}
+
+func (b *valueBinder) bindScalar(key string, data any, target reflect.Value) {
+	text := strings.TrimSpace(util.ToString(data))
+	var err error
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(util.ToString(data))
+	case reflect.Bool:
+		var value bool
+		if value, err = strconv.ParseBool(text); err == nil {
+			target.SetBool(value)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var value int64
+		if value, err = strconv.ParseInt(text, 10, target.Type().Bits()); err == nil {
+			target.SetInt(value)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var value uint64
+		if value, err = strconv.ParseUint(text, 10, target.Type().Bits()); err == nil {
+			target.SetUint(value)
+		}
+	case reflect.Float32, reflect.Float64:
+		var value float64
+		if value, err = strconv.ParseFloat(text, target.Type().Bits()); err == nil {
+			target.SetFloat(value)
+		}
+	default:
+		b.addErr(key, fmt.Sprintf("不支持的类型[%v]", target.Type()))
+		return
+	}
+	if err != nil {
+		b.addErr(key, fmt.Sprintf("值[%v]无法转换为%v类型", data, target.Type()))
+	}
+}
+
+// 有match标签并且不是结构体的属性；结构体中的属性在绑定该结构体时候单独校验
+func isCheckedField(field reflect.StructField) bool {
+	if field.Tag.Get("match") == "" {
+		return false
+	}
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind() != reflect.Struct || fieldType == timeType
+}
+
+// 属性对应的key：先yaml标签，后json标签；返回是否inline
+func fieldKeyName(field reflect.StructField) (string, bool) {
+	for _, tagName := range []string{"yaml", "json"} {
+		tag, exist := field.Tag.Lookup(tagName)
+		if !exist {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		for _, option := range parts[1:] {
+			if option == "inline" {
+				return "", true
+			}
+		}
+		if parts[0] != "" {
+			return parts[0], false
+		}
+	}
+	return "", false
+}
+
+// 在配置中查找属性对应的值：先精确匹配，后宽松匹配
+func lookupField(dataMap map[string]any, name string) (string, any, bool) {
+	if value, exist := dataMap[name]; exist {
+		return name, value, true
+	}
+	relaxedName := relaxedKey(name)
+	for key, value := range dataMap {
+		if relaxedKey(key) == relaxedName {
+			return key, value, true
+		}
+	}
+	return name, nil, false
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// 转换为时间间隔：支持3s、500ms、1h30m等写法，纯数字表示毫秒
+func toDuration(data any) (time.Duration, error) {
+	text := strings.TrimSpace(util.ToString(data))
+	if millis, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return time.Duration(millis) * time.Millisecond, nil
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("值[%v]不是合法的时间间隔，比如：3s、500ms", data)
+	}
+	return duration, nil
+}
+
+// 转换为时间：支持RFC3339、2006-01-02 15:04:05、2006-01-02等格式
+func toTime(data any) (time.Time, error) {
+	if value, ok := data.(time.Time); ok {
+		return value, nil
+	}
+	text := strings.TrimSpace(util.ToString(data))
+	for _, layout := range timeLayouts {
+		if value, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return value, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("值[%v]不是合法的时间，比如：2006-01-02 15:04:05、2006-01-02T15:04:05Z07:00", data)
+}
+
+// ParseByteSize 解析字节大小，比如：1024、512B、10KB、10MB、1GB、1GiB，单位不区分大小写
+func ParseByteSize(text string) (int64, error) {
+	text = strings.TrimSpace(text)
+	index := len(text)
+	for index > 0 && (text[index-1] < '0' || text[index-1] > '9') {
+		index--
+	}
+	unit, exist := byteSizeUnits[strings.ToLower(strings.TrimSpace(text[index:]))]
+	if !exist {
+		return 0, fmt.Errorf("值[%s]不是合法的字节大小，比如：10KB、10MB", text)
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(text[:index]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("值[%s]不是合法的字节大小，比如：10KB、10MB", text)
+	}
+	return int64(number * float64(unit)), nil
+}
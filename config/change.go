@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/simonalong/gole/listener"
+	"github.com/simonalong/gole/util"
+)
+
+// 对比新旧两个配置快照，对变更、新增以及删除的key发布配置变更事件
+func (c *Config) publishChangeEvents(oldProperty, newProperty *ApplicationProperty) {
+	for _, event := range diffProperty(oldProperty, newProperty) {
+		c.publishEvent(event)
+	}
+}
+
+// PlainValue 配置变更事件中key对应的明文值（当前的配置值）：变更事件中加密（ENC(...)）以及需要脱敏的配置值显示为******，需要明文的监听器通过该函数读取
+func PlainValue(event listener.ConfigChangeEvent) string {
+	return defaultConfig.PlainValue(event)
+}
+
+// PlainValue 配置变更事件中key对应的明文值，规则同包级别的PlainValue
+func (c *Config) PlainValue(event listener.ConfigChangeEvent) string {
+	return util.ToString(c.GetValue(event.Key))
+}
+
+// 对比新旧两个配置快照（占位符解析后的值），返回按照key排序的变更事件；删除的key对应的值为空字符串，
+// 事件中加密以及需要脱敏的配置值显示为******
+func diffProperty(oldProperty, newProperty *ApplicationProperty) []listener.ConfigChangeEvent {
+	oldValues := resolvedStringValues(oldProperty)
+	newValues := resolvedStringValues(newProperty)
+	displayValues := resolvedStringValues(displayProperty(newProperty))
+
+	var events []listener.ConfigChangeEvent
+	for key, newValue := range newValues {
+		if oldValue, exist := oldValues[key]; !exist || oldValue != newValue {
+			events = append(events, listener.ConfigChangeEvent{Key: key, Value: displayValues[key]})
+		}
+	}
+	for key := range oldValues {
+		if _, exist := newValues[key]; !exist {
+			events = append(events, listener.ConfigChangeEvent{Key: key, Value: ""})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Key < events[j].Key
+	})
+	return events
+}
+
+func resolvedStringValues(property *ApplicationProperty) map[string]string {
+	values := map[string]string{}
+	if nil == property {
+		return values
+	}
+	for key, value := range property.ValueMap {
+		if resolved, err := resolveValue(property, key, value); err == nil {
+			value = resolved
+		}
+		values[key] = util.ToString(value)
+	}
+	return values
+}
+
+// 变更事件中的值：加密以及需要脱敏的配置（包括对象中的属性）显示为******
+func maskedEventValue(property *ApplicationProperty, key string, data any) any {
+	switch value := data.(type) {
+	case map[string]any:
+		masked := make(map[string]any, len(value))
+		for childKey, childValue := range value {
+			masked[childKey] = maskedEventValue(property, joinKey(key, childKey), childValue)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(value))
+		for index, item := range value {
+			masked[index] = maskedEventValue(property, fmt.Sprintf("%s[%d]", key, index), item)
+		}
+		return masked
+	}
+	return maskValue(property, key, data)
+}
@@ -4,12 +4,13 @@ import (
 	"fmt"
 	"github.com/simonalong/gole/listener"
 	"log"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/simonalong/gole/file"
@@ -18,77 +19,102 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
-var appProperty *ApplicationProperty
-var configExist = false
-var loadLock sync.Mutex
-var configLoaded = false
+// CurrentProfile 默认实例当前激活的profile，多个用逗号分隔
 var CurrentProfile = ""
 
 func LoadConfig() {
-	loadLock.Lock()
-	defer loadLock.Unlock()
-	if configLoaded {
+	defaultConfig.LoadConfig()
+}
+
+// LoadConfig 加载当前目录下的配置，只加载一次
+func (c *Config) LoadConfig() {
+	c.loadLock.Lock()
+	defer c.loadLock.Unlock()
+	if c.loaded {
 		return
 	}
 
-	LoadConfigFromRelativePath("")
-	configLoaded = true
+	c.LoadConfigFromRelativePath("")
+	c.loaded = true
 }
 
 func LoadConfigFromRelativePath(resourceAbsPath string) {
+	defaultConfig.LoadConfigFromRelativePath(resourceAbsPath)
+}
+
+func (c *Config) LoadConfigFromRelativePath(resourceAbsPath string) {
 	dir, _ := os.Getwd()
 	pkg := strings.Replace(dir, "\\", "/", -1)
 
-	LoadConfigFromAbsPath(path.Join(pkg, "", resourceAbsPath))
+	c.LoadConfigFromAbsPath(path.Join(pkg, "", resourceAbsPath))
 }
 
 func LoadConfigFromAbsPath(resourceAbsPath string) {
-	doLoadConfigFromAbsPath(resourceAbsPath)
+	defaultConfig.LoadConfigFromAbsPath(resourceAbsPath)
+}
+
+func (c *Config) LoadConfigFromAbsPath(resourceAbsPath string) {
+	c.doLoadConfigFromAbsPath(resourceAbsPath)
 
-	cmPath := os.Getenv("gole.config.additional-location")
+	cmPath, _ := lookupExternalValue("gole.config.additional-location")
 	if cmPath == "" {
 		cmPath = "./config/application-default.yml"
 	}
-	AppendConfigFromRelativePath(cmPath)
+	c.appendConfigFile(toAbsPath(cmPath), SourceTypeAdditional)
 
-	ApiModule = GetValueString("api-module")
+	// 外部配置优先级：命令行 > 环境变量 > 配置文件
+	c.addPropertySource(&PropertySource{Name: SourceTypeEnvironment, Type: SourceTypeEnvironment, ValueMap: environmentValues(c.currentProperty(), os.Environ())})
+	c.addPropertySource(&PropertySource{Name: SourceTypeCommandLine, Type: SourceTypeCommandLine, ValueMap: commandLineValues(c.currentProperty(), os.Args[1:])})
+
+	// Kubernetes挂载的ConfigMap、Secret目录
+	c.loadConfigTrees()
+
+	// 包级别的配置对象（GoleCfg、ApiModule）只对应默认实例
+	if c == defaultConfig {
+		ApiModule = c.GetValueString("api-module")
+
+		if err := c.GetValueObject("gole", &GoleCfg); err != nil {
+			log.Printf("加载 Base 配置失败(%v)", err)
+		}
+	}
 
-	if err := GetValueObject("gole", &GoleCfg); err != nil {
-		log.Printf("加载 Base 配置失败(%v)", err)
+	// 配置文件的热加载
+	if c.GetValueBoolDefault("gole.config.watch.enable", false) {
+		interval, err := time.ParseDuration(c.GetValueStringDefault("gole.config.watch.interval", "5s"))
+		if err != nil {
+			log.Printf("gole.config.watch.interval 配置不合法(%v)，使用默认值5s", err)
+			interval = 5 * time.Second
+		}
+		c.StartWatchFiles(interval)
 	}
+
+	// 恢复持久化的配置变更记录
+	c.loadHistory()
+
+	// 未知以及废弃配置的提示
+	c.warnKeys()
 }
 
 func AppendConfigFromRelativePath(fileName string) {
-	dir, _ := os.Getwd()
-	pkg := strings.Replace(dir, "\\", "/", -1)
-	fileName = path.Join(pkg, "", fileName)
-	extend := getFileExtension(fileName)
-	extend = strings.ToLower(extend)
-	switch extend {
-	case "yaml":
-		AppendYamlFile(fileName)
-	case "yml":
-		AppendYamlFile(fileName)
-	case "properties":
-		AppendPropertyFile(fileName)
-	case "json":
-		AppendJsonFile(fileName)
-	}
+	defaultConfig.AppendConfigFromRelativePath(fileName)
+}
+
+func (c *Config) AppendConfigFromRelativePath(fileName string) {
+	c.AppendConfigFromAbsPath(toAbsPath(fileName))
 }
 
 func AppendConfigFromAbsPath(fileName string) {
-	extend := getFileExtension(fileName)
-	extend = strings.ToLower(extend)
-	switch extend {
-	case "yaml":
-		AppendYamlFile(fileName)
-	case "yml":
-		AppendYamlFile(fileName)
-	case "properties":
-		AppendPropertyFile(fileName)
-	case "json":
-		AppendJsonFile(fileName)
-	}
+	defaultConfig.AppendConfigFromAbsPath(fileName)
+}
+
+func (c *Config) AppendConfigFromAbsPath(fileName string) {
+	c.appendConfigFile(fileName, SourceTypeFile)
+}
+
+func toAbsPath(fileName string) string {
+	dir, _ := os.Getwd()
+	pkg := strings.Replace(dir, "\\", "/", -1)
+	return path.Join(pkg, "", fileName)
 }
 
 type EnvProperty struct {
@@ -97,175 +123,249 @@ type EnvProperty struct {
 }
 
 func ExistConfigFile() bool {
-	return configExist
+	return defaultConfig.ExistConfigFile()
+}
+
+func (c *Config) ExistConfigFile() bool {
+	exist, _ := c.exist.Load().(bool)
+	return exist
 }
 
+// GetConfigValues 查看所有配置；带上参数origin=true时候返回每个配置的来源、被覆盖的值以及配置源的优先级；需要脱敏的配置显示为******
 func GetConfigValues(c *gin.Context) {
-	if nil != appProperty {
-		c.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(appProperty.ValueMap)))
+	defaultConfig.GetConfigValues(c)
+}
+
+// GetConfigValues 查看所有配置，规则同包级别的GetConfigValues
+func (c *Config) GetConfigValues(ctx *gin.Context) {
+	if !checkEndpointAuth(ctx) {
+		return
+	}
+	if property := c.currentProperty(); nil != property {
+		display := displayProperty(property)
+		if ctx.Query("origin") == "true" {
+			origins := map[string]any{}
+			for key := range display.ValueMap {
+				origins[key] = valueOrigin(display, key)
+			}
+			ctx.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(map[string]any{"sources": c.GetPropertySources(), "values": origins})))
+			return
+		}
+		ctx.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(display.ValueMap)))
 	} else {
-		c.Data(200, "application/json; charset=utf-8", []byte("{}"))
+		ctx.Data(200, "application/json; charset=utf-8", []byte("{}"))
 	}
 }
 
 func GetConfigDeepValues(c *gin.Context) {
-	if nil != appProperty {
-		c.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(appProperty.ValueDeepMap)))
+	defaultConfig.GetConfigDeepValues(c)
+}
+
+func (c *Config) GetConfigDeepValues(ctx *gin.Context) {
+	if !checkEndpointAuth(ctx) {
+		return
+	}
+	if property := c.currentProperty(); nil != property {
+		ctx.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(displayProperty(property).ValueDeepMap)))
 	} else {
-		c.Data(200, "application/json; charset=utf-8", []byte("{}"))
+		ctx.Data(200, "application/json; charset=utf-8", []byte("{}"))
 	}
 }
 
+// GetConfigValue 查看某个配置；带上参数origin=true时候返回配置的来源以及被覆盖的值；加密配置的值不会解密，需要脱敏的配置显示为******
 func GetConfigValue(c *gin.Context) {
-	if nil != appProperty {
-		value := GetValue(c.Param("key"))
+	defaultConfig.GetConfigValue(c)
+}
+
+// GetConfigValue 查看某个配置，规则同包级别的GetConfigValue
+func (c *Config) GetConfigValue(ctx *gin.Context) {
+	if !checkEndpointAuth(ctx) {
+		return
+	}
+	if property := c.currentProperty(); nil != property {
+		if ctx.Query("origin") == "true" {
+			origin := c.GetValueOrigin(ctx.Param("key"))
+			if nil == origin {
+				ctx.Data(200, "application/json; charset=utf-8", []byte("{}"))
+				return
+			}
+			// ObjectToJson不支持指针
+			ctx.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(*origin)))
+			return
+		}
+		value := displayValue(property, ctx.Param("key"))
 		if nil == value {
-			c.Data(200, "application/json; charset=utf-8", []byte(""))
+			ctx.Data(200, "application/json; charset=utf-8", []byte(""))
 			return
 		}
 		if util.IsBaseType(reflect.TypeOf(value)) {
-			c.Data(200, "application/json; charset=utf-8", []byte(util.ToString(value)))
+			ctx.Data(200, "application/json; charset=utf-8", []byte(util.ToString(value)))
 		} else {
-			c.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(value)))
+			ctx.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(value)))
 		}
 	} else {
-		c.Data(200, "application/json; charset=utf-8", []byte("{}"))
+		ctx.Data(200, "application/json; charset=utf-8", []byte("{}"))
 	}
 }
 
+// UpdateConfig 修改配置，只能修改gole.endpoint.config.update.allow-keys中允许的配置；每次修改都会记录调用方ip、修改前后的值
 func UpdateConfig(c *gin.Context) {
+	defaultConfig.UpdateConfig(c)
+}
+
+// UpdateConfig 修改配置，规则同包级别的UpdateConfig
+func (c *Config) UpdateConfig(ctx *gin.Context) {
+	if !checkEndpointAuth(ctx) {
+		return
+	}
 	valueMap := map[string]any{}
-	err := util.DataToObject(c.Request.Body, &valueMap)
+	err := util.DataToObject(ctx.Request.Body, &valueMap)
 	if err != nil {
 		log.Printf("解析失败，%v", err.Error())
 		return
 	}
 
-	key, _ := valueMap["key"]
+	key, _ := valueMap["key"].(string)
 	value, _ := valueMap["value"]
+	if key == "" {
+		ctx.Data(http.StatusBadRequest, "application/json; charset=utf-8", []byte(util.ObjectToJson(map[string]any{"code": http.StatusBadRequest, "message": "key不能为空"})))
+		return
+	}
+
+	property := c.currentProperty()
+	record := UpdateRecord{Time: time.Now(), Ip: ctx.ClientIP(), Key: key, OldValue: displayValue(property, key), Allowed: isUpdateAllowed(property, key)}
+	record.NewValue = maskValue(property, key, value)
+	if !record.Allowed {
+		auditUpdate(record)
+		ctx.Data(http.StatusForbidden, "application/json; charset=utf-8", []byte(util.ObjectToJson(map[string]any{"code": http.StatusForbidden, "message": fmt.Sprintf("配置[%s]不允许修改", key)})))
+		return
+	}
+
+	c.setValue(key, value, ChangeSourceEndpoint+":"+record.Ip)
+	auditUpdate(record)
+}
+
+// 用于查看的配置值：加密以及需要脱敏的配置显示为******
+func displayValue(property *ApplicationProperty, key string) any {
+	display := displayProperty(property)
+	if nil == display {
+		return nil
+	}
+	key = display.canonicalKey(key)
+	return resolveValueOrRaw(display, key, doGetValue(display.ValueDeepMap, key))
+}
 
-	SetValue(key.(string), value)
+// 需要脱敏的配置以及加密的配置值显示为******
+func maskValue(property *ApplicationProperty, key string, value any) any {
+	if text, ok := value.(string); (ok && isEncryptedValue(text)) || isMaskedKey(maskPatterns(property), key) {
+		return maskedValue
+	}
+	return value
 }
 
-// 多种格式优先级：json > properties > yaml > yml
-func doLoadConfigFromAbsPath(resourceAbsPath string) {
+// 配置文件支持的格式，同时存在多个的时候后面的生效：json > properties > ini > toml > yml > yaml
+var configFileExtensions = []string{"yaml", "yml", "toml", "ini", "properties", "json"}
+
+// 多种格式优先级：json > properties > ini > toml > yml > yaml；激活的profile按照顺序叠加，后面的优先级高
+func (c *Config) doLoadConfigFromAbsPath(resourceAbsPath string) {
 	if !strings.HasSuffix(resourceAbsPath, "/") {
 		resourceAbsPath += "/"
 	}
-	files, err := os.ReadDir(resourceAbsPath)
-	if err != nil {
+	if _, err := os.ReadDir(resourceAbsPath); err != nil {
 		return
 	}
 
-	if appProperty == nil {
-		appProperty = &ApplicationProperty{}
-		appProperty.ValueMap = make(map[string]interface{})
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	} else if appProperty.ValueMap == nil {
-		appProperty.ValueMap = make(map[string]interface{})
-	} else if appProperty.ValueDeepMap == nil {
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	}
+	c.setActiveProfiles(nil)
+	c.loadDefaultConfigFiles(resourceAbsPath)
 
-	LoadYamlFile(resourceAbsPath + "application.yaml")
-	LoadYamlFile(resourceAbsPath + "application.yml")
-	LoadPropertyFile(resourceAbsPath + "application.properties")
-	LoadJsonFile(resourceAbsPath + "application.json")
+	profiles := c.getActiveProfiles()
+	if len(profiles) == 0 {
+		c.appendDotenvFile(resourceAbsPath)
+		return
+	}
+	activeProfile := strings.Join(profiles, ",")
+	if c == defaultConfig {
+		CurrentProfile = activeProfile
+	}
+	c.setActiveProfiles(profiles)
 
-	for _, fileInfo := range files {
-		if fileInfo.IsDir() {
-			continue
-		}
+	// 重新加载默认配置，使其中对应profile的文档生效
+	c.loadDefaultConfigFiles(resourceAbsPath)
+	c.setActiveProfileValue(activeProfile)
 
-		fileName := fileInfo.Name()
-		if !strings.HasPrefix(fileName, "application") {
-			continue
+	for _, profile := range profiles {
+		for _, extension := range configFileExtensions {
+			filePath := resourceAbsPath + "application-" + profile + "." + extension
+			if file.FileExists(filePath) {
+				c.exist.Store(true)
+				c.appendConfigFile(filePath, SourceTypeProfile)
+			}
 		}
+	}
+	c.appendDotenvFile(resourceAbsPath)
+}
 
-		// 默认配置
-		if fileName == "application.yaml" {
-			configExist = true
-			break
-		} else if fileName == "application.yml" {
-			configExist = true
-			break
-		} else if fileName == "application.properties" {
-			configExist = true
-			break
-		} else if fileName == "application.json" {
-			configExist = true
-			break
+func (c *Config) loadDefaultConfigFiles(resourceAbsPath string) {
+	for _, extension := range configFileExtensions {
+		if file.FileExists(resourceAbsPath + "application." + extension) {
+			c.exist.Store(true)
+			c.LoadFile(resourceAbsPath + "application." + extension)
 		}
+	}
+}
 
-		profile := getActiveProfile()
-		if profile != "" {
-			CurrentProfile = profile
-			SetValue("gole.profiles.active", profile)
-			currentProfile := getProfileFromFileName(fileName)
-			if currentProfile == profile {
-				configExist = true
-				AppendFile(resourceAbsPath + fileName)
-			}
-		}
+// 本地开发用的.env文件，优先级高于application的配置文件，低于环境变量
+func (c *Config) appendDotenvFile(resourceAbsPath string) {
+	if file.FileExists(resourceAbsPath + ".env") {
+		c.appendFileSource(resourceAbsPath+".env", SourceTypeFile, c.dotenvContentToMap)
 	}
 }
 
 func LoadFile(filePath string) {
-	extend := getFileExtension(filePath)
-	extend = strings.ToLower(extend)
-	if extend == "yaml" {
-		configExist = true
-		LoadYamlFile(filePath)
-	} else if extend == "yml" {
-		configExist = true
-		LoadYamlFile(filePath)
-	} else if extend == "properties" {
-		configExist = true
-		LoadPropertyFile(filePath)
-	} else if extend == "json" {
-		configExist = true
-		LoadJsonFile(filePath)
+	defaultConfig.LoadFile(filePath)
+}
+
+// LoadFile 按照文件后缀解析配置文件，清理之前所有的配置源，只保留该文件以及其导入的文件
+func (c *Config) LoadFile(filePath string) {
+	if parser := c.fileParser(filePath); parser != nil {
+		c.exist.Store(true)
+		c.loadFileSource(filePath, parser)
 	}
 }
 
 func AppendFile(filePath string) {
-	extend := getFileExtension(filePath)
-	extend = strings.ToLower(extend)
-	if extend == "yaml" {
-		AppendYamlFile(filePath)
-	} else if extend == "yml" {
-		AppendYamlFile(filePath)
-	} else if extend == "properties" {
-		AppendPropertyFile(filePath)
-	} else if extend == "json" {
-		AppendJsonFile(filePath)
-	}
+	defaultConfig.AppendFile(filePath)
 }
 
-// 临时写死
-// 优先级：环境变量 > 本地配置
-func getActiveProfile() string {
-	profile := os.Getenv("gole.profiles.active")
-	if profile != "" {
-		return profile
-	}
+// AppendFile 按照文件后缀解析配置文件，并叠加到当前配置中
+func (c *Config) AppendFile(filePath string) {
+	c.appendConfigFile(filePath, SourceTypeFile)
+}
 
-	profile = GetValueString("gole.profiles.active")
-	if profile != "" {
-		return profile
+// 按照文件后缀解析配置文件，并作为对应类型的配置源叠加到当前配置中
+func (c *Config) appendConfigFile(filePath string, sourceType string) {
+	if parser := c.fileParser(filePath); parser != nil {
+		c.appendFileSource(filePath, sourceType, parser)
 	}
-	return ""
 }
 
-func getProfileFromFileName(fileName string) string {
-	if strings.HasPrefix(fileName, "application-") {
-		words := strings.SplitN(fileName, ".", 2)
-		appNames := words[0]
-
-		appNameAndProfile := strings.SplitN(appNames, "-", 2)
-		return appNameAndProfile[1]
+// 文件后缀对应的解析器，不支持的格式返回nil；yaml（多文档的profile条件）以及.env（宽松匹配已有的key）的解析依赖当前实例
+func (c *Config) fileParser(filePath string) func(string) (map[string]any, error) {
+	switch strings.ToLower(getFileExtension(filePath)) {
+	case "yaml", "yml":
+		return c.yamlContentToMap
+	case "properties":
+		return util.PropertiesToRawMap
+	case "json":
+		return c.jsonContentToMap
+	case "toml":
+		return tomlContentToMap
+	case "ini":
+		return iniContentToMap
+	case "env":
+		return c.dotenvContentToMap
 	}
-	return ""
+	return nil
 }
 
 func getFileExtension(fileName string) string {
@@ -279,259 +379,239 @@ func getFileExtension(fileName string) string {
 }
 
 func LoadYamlFile(filePath string) {
-	if !file.FileExists(filePath) {
-		return
-	}
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		// log.Printf("读取文件失败(%v)", err)
-		return
-	}
+	defaultConfig.LoadYamlFile(filePath)
+}
 
-	if appProperty == nil {
-		appProperty = &ApplicationProperty{}
-		appProperty.ValueMap = make(map[string]interface{})
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	} else if appProperty.ValueMap == nil {
-		appProperty.ValueMap = make(map[string]interface{})
-	} else if appProperty.ValueDeepMap == nil {
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	}
+func (c *Config) LoadYamlFile(filePath string) {
+	c.loadFileSource(filePath, c.yamlContentToMap)
+}
 
-	property, err := util.YamlToProperties(string(content))
-	if err != nil {
-		return
-	}
-	valueMap, _ := util.PropertiesToMap(property)
-	appProperty.ValueMap = valueMap
+func AppendYamlFile(filePath string) {
+	defaultConfig.AppendYamlFile(filePath)
+}
 
-	yamlMap, err := util.YamlToMap(string(content))
-	if err != nil {
-		return
-	}
-	appProperty.ValueDeepMap = yamlMap
+func (c *Config) AppendYamlFile(filePath string) {
+	c.appendFileSource(filePath, SourceTypeFile, c.yamlContentToMap)
 }
 
-func AppendYamlFile(filePath string) {
-	if !file.FileExists(filePath) {
-		return
-	}
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		// log.Printf("读取文件失败(%v)", err)
-		return
-	}
+func LoadPropertyFile(filePath string) {
+	defaultConfig.LoadPropertyFile(filePath)
+}
 
-	if appProperty == nil {
-		appProperty = &ApplicationProperty{}
-		appProperty.ValueMap = make(map[string]interface{})
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	} else if appProperty.ValueMap == nil {
-		appProperty.ValueMap = make(map[string]interface{})
-	} else if appProperty.ValueDeepMap == nil {
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	}
+func (c *Config) LoadPropertyFile(filePath string) {
+	c.loadFileSource(filePath, util.PropertiesToRawMap)
+}
 
-	property, err := util.YamlToProperties(string(content))
-	if err != nil {
-		return
-	}
-	AppendValue(property)
+func AppendPropertyFile(filePath string) {
+	defaultConfig.AppendPropertyFile(filePath)
 }
 
-func LoadPropertyFile(filePath string) {
-	if !file.FileExists(filePath) {
-		return
-	}
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		// log.Printf("读取文件失败(%v)", err)
-		return
-	}
+func (c *Config) AppendPropertyFile(filePath string) {
+	c.appendFileSource(filePath, SourceTypeFile, util.PropertiesToRawMap)
+}
 
-	if appProperty == nil {
-		appProperty = &ApplicationProperty{}
-		appProperty.ValueMap = make(map[string]interface{})
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	} else if appProperty.ValueMap == nil {
-		appProperty.ValueMap = make(map[string]interface{})
-	} else if appProperty.ValueDeepMap == nil {
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	}
+func LoadJsonFile(filePath string) {
+	defaultConfig.LoadJsonFile(filePath)
+}
 
-	valueMap, _ := util.PropertiesToMap(string(content))
-	appProperty.ValueMap = valueMap
+func (c *Config) LoadJsonFile(filePath string) {
+	c.loadFileSource(filePath, c.jsonContentToMap)
+}
 
-	yamlStr, _ := util.PropertiesToYaml(string(content))
-	yamlMap, _ := util.YamlToMap(yamlStr)
-	appProperty.ValueDeepMap = yamlMap
+func AppendJsonFile(filePath string) {
+	defaultConfig.AppendJsonFile(filePath)
 }
 
-func AppendPropertyFile(filePath string) {
-	if !file.FileExists(filePath) {
-		return
-	}
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Printf("读取文件失败(%v)", err)
-		return
-	}
+func (c *Config) AppendJsonFile(filePath string) {
+	c.appendFileSource(filePath, SourceTypeFile, c.jsonContentToMap)
+}
 
-	if appProperty == nil {
-		appProperty = &ApplicationProperty{}
-		appProperty.ValueMap = make(map[string]interface{})
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	} else if appProperty.ValueMap == nil {
-		appProperty.ValueMap = make(map[string]interface{})
-	} else if appProperty.ValueDeepMap == nil {
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	}
+func LoadTomlFile(filePath string) {
+	defaultConfig.LoadTomlFile(filePath)
+}
 
-	valueMap, err := util.PropertiesToMap(string(content))
-	if err != nil {
-		return
-	}
-	propertiesValue, err := util.MapToProperties(valueMap)
-	if err != nil {
-		return
-	}
+func (c *Config) LoadTomlFile(filePath string) {
+	c.loadFileSource(filePath, tomlContentToMap)
+}
 
-	AppendValue(propertiesValue)
+func AppendTomlFile(filePath string) {
+	defaultConfig.AppendTomlFile(filePath)
 }
 
-func LoadJsonFile(filePath string) {
-	if !file.FileExists(filePath) {
-		return
-	}
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		// log.Printf("读取文件失败(%v)", err)
-		return
-	}
+func (c *Config) AppendTomlFile(filePath string) {
+	c.appendFileSource(filePath, SourceTypeFile, tomlContentToMap)
+}
 
-	if appProperty == nil {
-		appProperty = &ApplicationProperty{}
-		appProperty.ValueMap = make(map[string]interface{})
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	} else if appProperty.ValueMap == nil {
-		appProperty.ValueMap = make(map[string]interface{})
-	} else if appProperty.ValueDeepMap == nil {
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	}
+func LoadIniFile(filePath string) {
+	defaultConfig.LoadIniFile(filePath)
+}
 
-	yamlStr, _ := util.JsonToYaml(string(content))
-	property, _ := util.YamlToProperties(yamlStr)
-	valueMap, _ := util.PropertiesToMap(property)
-	appProperty.ValueMap = valueMap
+func (c *Config) LoadIniFile(filePath string) {
+	c.loadFileSource(filePath, iniContentToMap)
+}
 
-	yamlMap, _ := util.YamlToMap(yamlStr)
-	appProperty.ValueDeepMap = yamlMap
+func AppendIniFile(filePath string) {
+	defaultConfig.AppendIniFile(filePath)
 }
 
-func AppendJsonFile(filePath string) {
-	if !file.FileExists(filePath) {
-		return
-	}
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Printf("读取文件失败(%v)", err)
-		return
-	}
+func (c *Config) AppendIniFile(filePath string) {
+	c.appendFileSource(filePath, SourceTypeFile, iniContentToMap)
+}
 
-	if appProperty == nil {
-		appProperty = &ApplicationProperty{}
-		appProperty.ValueMap = make(map[string]interface{})
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	} else if appProperty.ValueMap == nil {
-		appProperty.ValueMap = make(map[string]interface{})
-	} else if appProperty.ValueDeepMap == nil {
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	}
+func LoadEnvFile(filePath string) {
+	defaultConfig.LoadEnvFile(filePath)
+}
+
+func (c *Config) LoadEnvFile(filePath string) {
+	c.loadFileSource(filePath, c.dotenvContentToMap)
+}
 
-	yamlStr, err := util.JsonToYaml(string(content))
+func AppendEnvFile(filePath string) {
+	defaultConfig.AppendEnvFile(filePath)
+}
+
+func (c *Config) AppendEnvFile(filePath string) {
+	c.appendFileSource(filePath, SourceTypeFile, c.dotenvContentToMap)
+}
+
+func tomlContentToMap(content string) (map[string]any, error) {
+	dataMap, err := util.TomlToMap(content)
 	if err != nil {
-		return
+		return nil, err
 	}
-	property, err := util.YamlToProperties(yamlStr)
+	return util.DeepMapToPropertiesMap(dataMap), nil
+}
+
+func iniContentToMap(content string) (map[string]any, error) {
+	property, err := util.IniToProperties(content)
 	if err != nil {
-		return
+		return nil, err
 	}
-
-	AppendValue(property)
+	return util.PropertiesToRawMap(property)
 }
 
-func AppendValue(propertiesNewValue string) {
-	pMap, err := util.PropertiesToMap(propertiesNewValue)
-	for k, v := range pMap {
-		appProperty.ValueMap[k] = v
+// .env中的变量与环境变量的规则相同，比如：GOLE_SERVER_PORT -> gole.server.port；
+// 没有匹配到配置的变量保留原始的变量名，便于通过占位符引用，比如：${DB_PASSWORD}
+func (c *Config) dotenvContentToMap(content string) (map[string]any, error) {
+	dataMap, err := util.DotenvToMap(content)
+	if err != nil {
+		return nil, err
 	}
+	var env []string
+	for name, value := range dataMap {
+		env = append(env, name+"="+util.ToString(value))
+	}
+	property := c.currentProperty()
+	valueMap := environmentValues(property, env)
 
-	propertiesValueOfOriginal, err := util.MapToProperties(appProperty.ValueMap)
-	if err != nil {
-		return
+	keyIndex := relaxedKeyIndex(property)
+	for name, value := range dataMap {
+		if strings.HasPrefix(name, envGolePrefix) || strings.Contains(name, ".") {
+			continue
+		}
+		if _, matched := keyIndex[relaxedKey(envNameToKey(name))]; !matched {
+			valueMap[name] = value
+		}
 	}
+	return valueMap, nil
+}
 
-	resultYaml, err := util.PropertiesToYaml(propertiesValueOfOriginal)
+func (c *Config) jsonContentToMap(content string) (map[string]any, error) {
+	yamlStr, err := util.JsonToYaml(content)
 	if err != nil {
-		return
+		return nil, err
 	}
-	resultDeepMap, err := util.YamlToMap(resultYaml)
-	if err != nil {
+	return c.yamlContentToMap(yamlStr)
+}
+
+// 加载配置文件：清理之前所有的配置源，只保留该文件以及其导入的文件
+func (c *Config) loadFileSource(filePath string, parser func(string) (map[string]any, error)) {
+	sources := c.readFileSources(filePath, SourceTypeFile, parser, nil)
+	if len(sources) == 0 {
 		return
 	}
-	appProperty.ValueDeepMap = resultDeepMap
+	c.replacePropertySources(sources...)
 }
 
-func SetValue(key string, value any) {
-	if nil == value {
+// 叠加配置文件：作为新的配置源，优先级高于之前加载的同类型配置源
+func (c *Config) appendFileSource(filePath string, sourceType string, parser func(string) (map[string]any, error)) {
+	sources := c.readFileSources(filePath, sourceType, parser, nil)
+	if len(sources) == 0 {
 		return
 	}
-	if appProperty == nil {
-		appProperty = &ApplicationProperty{}
-		appProperty.ValueMap = make(map[string]interface{})
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	} else if appProperty.ValueMap == nil {
-		appProperty.ValueMap = make(map[string]interface{})
-	} else if appProperty.ValueDeepMap == nil {
-		appProperty.ValueDeepMap = make(map[string]interface{})
-	}
+	c.addPropertySource(sources...)
+}
 
-	if oldValue, exist := appProperty.ValueMap[key]; exist {
-		if !util.IsBaseType(reflect.TypeOf(oldValue)) {
-			if reflect.TypeOf(oldValue) != reflect.TypeOf(value) {
-				return
-			}
-		}
+func readFileSource(filePath string, parser func(string) (map[string]any, error)) (map[string]any, bool) {
+	if !file.FileExists(filePath) {
+		return nil, false
 	}
-	propertiesValueOfOriginal, err := util.MapToProperties(appProperty.ValueDeepMap)
+	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return
+		log.Printf("读取文件失败(%v)", err)
+		return nil, false
 	}
-	resultMap, err := util.PropertiesToMap(propertiesValueOfOriginal)
+	valueMap, err := parser(string(content))
 	if err != nil {
-		return
+		log.Printf("解析配置文件[%s]失败(%v)", filePath, err)
+		return nil, false
 	}
+	return valueMap, true
+}
 
-	resultMap, err = parseProperties(key, value, resultMap)
-
-	appProperty.ValueMap = resultMap
+// AppendValue 叠加properties格式的配置，作为运行时配置生效
+func AppendValue(propertiesNewValue string) {
+	defaultConfig.AppendValue(propertiesNewValue)
+}
 
-	mapProperties, err := util.MapToProperties(resultMap)
+// AppendValue 叠加properties格式的配置，作为运行时配置生效；其中的数组按照gole.config.merge.lists配置的方式合并，默认整体替换
+func (c *Config) AppendValue(propertiesNewValue string) {
+	pMap, err := util.PropertiesToRawMap(propertiesNewValue)
 	if err != nil {
 		return
 	}
-	mapYaml, err := util.PropertiesToYaml(mapProperties)
-	if err != nil {
+	oldProperty, newProperty, _ := c.updateRuntimeValues(ChangeSourceCode, func(property *ApplicationProperty, valueMap map[string]any) bool {
+		mergeValues(valueMap, pMap, listMergeStrategies(property.ValueMap), property.canonicalIndex)
+		return true
+	})
+
+	// 发布配置变更事件
+	c.publishChangeEvents(oldProperty, newProperty)
+}
+
+func SetValue(key string, value any) {
+	defaultConfig.SetValue(key, value)
+}
+
+// SetValue 修改运行时配置，value可以为对象、数组
+func (c *Config) SetValue(key string, value any) {
+	c.setValue(key, value, ChangeSourceCode)
+}
+
+// 修改运行时配置，source为变更记录中的来源
+func (c *Config) setValue(key string, value any, source string) {
+	if nil == value {
 		return
 	}
-	resultDeepMap, err := util.YamlToMap(mapYaml)
-	if err != nil {
+	_, newProperty, changed := c.updateRuntimeValues(source, func(property *ApplicationProperty, valueMap map[string]any) bool {
+		key = property.canonicalKey(key)
+		if oldValue, exist := property.ValueMap[key]; exist {
+			if !util.IsBaseType(reflect.TypeOf(oldValue)) {
+				if reflect.TypeOf(oldValue) != reflect.TypeOf(value) {
+					return false
+				}
+			}
+		}
+		newValues, _ := parseProperties(key, value, map[string]any{})
+		mergeValues(valueMap, newValues, listMergeStrategies(property.ValueMap), property.canonicalIndex)
+		return true
+	})
+	if !changed {
 		return
 	}
-	appProperty.ValueDeepMap = resultDeepMap
 
 	// 发布配置变更事件
-	listener.PublishEvent(listener.ConfigChangeEvent{Key: key, Value: util.ToString(util.ObjectToData(value))})
+	c.publishEvent(listener.ConfigChangeEvent{Key: key, Value: util.ToString(maskedEventValue(newProperty, key, util.ObjectToData(value)))})
 }
 
 func parseProperties(key string, value any, resultMap map[string]any) (map[string]any, error) {
@@ -550,304 +630,331 @@ func parseProperties(key string, value any, resultMap map[string]any) (map[strin
 			return resultMap, err
 		}
 		for i, v := range values {
-			resultMap[key+"["+util.ToString(i)+"]"] = v
 			resultMap, err = parseProperties(key+"["+util.ToString(i)+"]", v, resultMap)
 		}
 	} else {
-		if reflect.ValueOf(value).Kind() == reflect.String && util.ToString(value) != "" {
-			resultMap[key] = value
-		} else if value == nil {
-			resultMap[key] = value
-		}
+		resultMap[key] = value
 	}
 	return resultMap, nil
 }
 
 func GetValueString(key string) string {
-	if nil == appProperty {
-		return ""
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueString(key)
+}
+
+func (c *Config) GetValueString(key string) string {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToString(value)
 	}
 	return ""
 }
 
 func GetValueInt(key string) int {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt(key)
+}
+
+func (c *Config) GetValueInt(key string) int {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt(value)
 	}
 	return 0
 }
 
 func GetValueInt8(key string) int8 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt8(key)
+}
+
+func (c *Config) GetValueInt8(key string) int8 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt8(value)
 	}
 	return 0
 }
 
 func GetValueInt16(key string) int16 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt16(key)
+}
+
+func (c *Config) GetValueInt16(key string) int16 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt16(value)
 	}
 	return 0
 }
 
 func GetValueInt32(key string) int32 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt32(key)
+}
+
+func (c *Config) GetValueInt32(key string) int32 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt32(value)
 	}
 	return 0
 }
 
 func GetValueInt64(key string) int64 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt64(key)
+}
+
+func (c *Config) GetValueInt64(key string) int64 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt64(value)
 	}
 	return 0
 }
 
 func GetValueUInt(key string) uint {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt(key)
+}
+
+func (c *Config) GetValueUInt(key string) uint {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt(value)
 	}
 	return 0
 }
 
 func GetValueUInt8(key string) uint8 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt8(key)
+}
+
+func (c *Config) GetValueUInt8(key string) uint8 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt8(value)
 	}
 	return 0
 }
 
 func GetValueUInt16(key string) uint16 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt16(key)
+}
+
+func (c *Config) GetValueUInt16(key string) uint16 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt16(value)
 	}
 	return 0
 }
 
 func GetValueUInt32(key string) uint32 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt32(key)
+}
+
+func (c *Config) GetValueUInt32(key string) uint32 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt32(value)
 	}
 	return 0
 }
 
 func GetValueUInt64(key string) uint64 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt64(key)
+}
+
+func (c *Config) GetValueUInt64(key string) uint64 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt64(value)
 	}
 	return 0
 }
 
 func GetValueFloat32(key string) float32 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueFloat32(key)
+}
+
+func (c *Config) GetValueFloat32(key string) float32 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToFloat32(value)
 	}
 	return 0
 }
 
 func GetValueFloat64(key string) float64 {
-	if nil == appProperty {
-		return 0
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueFloat64(key)
+}
+
+func (c *Config) GetValueFloat64(key string) float64 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToFloat64(value)
 	}
 	return 0
 }
 
 func GetValueBool(key string) bool {
-	if nil == appProperty {
-		return false
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueBool(key)
+}
+
+func (c *Config) GetValueBool(key string) bool {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToBool(value)
 	}
 	return false
 }
 
 func GetValueStringDefault(key, defaultValue string) string {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueStringDefault(key, defaultValue)
+}
+
+func (c *Config) GetValueStringDefault(key, defaultValue string) string {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToString(value)
 	}
 	return defaultValue
 }
 
 func GetValueIntDefault(key string, defaultValue int) int {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueIntDefault(key, defaultValue)
+}
+
+func (c *Config) GetValueIntDefault(key string, defaultValue int) int {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt(value)
 	}
 	return defaultValue
 }
 
 func GetValueInt8Default(key string, defaultValue int8) int8 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt8Default(key, defaultValue)
+}
+
+func (c *Config) GetValueInt8Default(key string, defaultValue int8) int8 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt8(value)
 	}
 	return defaultValue
 }
 
 func GetValueInt16Default(key string, defaultValue int16) int16 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt16Default(key, defaultValue)
+}
+
+func (c *Config) GetValueInt16Default(key string, defaultValue int16) int16 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt16(value)
 	}
 	return defaultValue
 }
 
 func GetValueInt32Default(key string, defaultValue int32) int32 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt32Default(key, defaultValue)
+}
+
+func (c *Config) GetValueInt32Default(key string, defaultValue int32) int32 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt32(value)
 	}
 	return defaultValue
 }
 
 func GetValueInt64Default(key string, defaultValue int64) int64 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueInt64Default(key, defaultValue)
+}
+
+func (c *Config) GetValueInt64Default(key string, defaultValue int64) int64 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToInt64(value)
 	}
 	return defaultValue
 }
 
 func GetValueUIntDefault(key string, defaultValue uint) uint {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUIntDefault(key, defaultValue)
+}
+
+func (c *Config) GetValueUIntDefault(key string, defaultValue uint) uint {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt(value)
 	}
 	return defaultValue
 }
 
 func GetValueUInt8Default(key string, defaultValue uint8) uint8 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt8Default(key, defaultValue)
+}
+
+func (c *Config) GetValueUInt8Default(key string, defaultValue uint8) uint8 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt8(value)
 	}
 	return defaultValue
 }
 
 func GetValueUInt16Default(key string, defaultValue uint16) uint16 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt16Default(key, defaultValue)
+}
+
+func (c *Config) GetValueUInt16Default(key string, defaultValue uint16) uint16 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt16(value)
 	}
 	return defaultValue
 }
 
 func GetValueUInt32Default(key string, defaultValue uint32) uint32 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt32Default(key, defaultValue)
+}
+
+func (c *Config) GetValueUInt32Default(key string, defaultValue uint32) uint32 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt32(value)
 	}
 	return defaultValue
 }
 
 func GetValueUInt64Default(key string, defaultValue uint64) uint64 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueUInt64Default(key, defaultValue)
+}
+
+func (c *Config) GetValueUInt64Default(key string, defaultValue uint64) uint64 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToUInt64(value)
 	}
 	return defaultValue
 }
 
 func GetValueFloat32Default(key string, defaultValue float32) float32 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueFloat32Default(key, defaultValue)
+}
+
+func (c *Config) GetValueFloat32Default(key string, defaultValue float32) float32 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToFloat32(value)
 	}
 	return defaultValue
 }
 
 func GetValueFloat64Default(key string, defaultValue float64) float64 {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueFloat64Default(key, defaultValue)
+}
+
+func (c *Config) GetValueFloat64Default(key string, defaultValue float64) float64 {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToFloat64(value)
 	}
 	return defaultValue
 }
 
 func GetValueBoolDefault(key string, defaultValue bool) bool {
-	if nil == appProperty {
-		return defaultValue
-	}
-	if value, exist := appProperty.ValueMap[key]; exist {
+	return defaultConfig.GetValueBoolDefault(key, defaultValue)
+}
+
+func (c *Config) GetValueBoolDefault(key string, defaultValue bool) bool {
+	if value, exist := c.lookupValue(key); exist {
 		return util.ToBool(value)
 	}
 	return defaultValue
 }
 
 func GetValueObject(key string, targetPtrObj any) error {
-	if nil == appProperty {
-		return nil
+	return defaultConfig.GetValueObject(key, targetPtrObj)
+}
+
+func (c *Config) GetValueObject(key string, targetPtrObj any) error {
+	data := c.getDeepValue(key)
+	if nil != targetPtrObj {
+		data = alignFieldKeys(data, reflect.TypeOf(targetPtrObj))
 	}
-	data := doGetValue(appProperty.ValueDeepMap, key)
 	err := util.DataToObject(data, targetPtrObj)
 	if err != nil {
 		return err
@@ -856,12 +963,12 @@ func GetValueObject(key string, targetPtrObj any) error {
 }
 
 func GetValueArray(key string) []any {
-	if nil == appProperty {
-		return nil
-	}
+	return defaultConfig.GetValueArray(key)
+}
 
+func (c *Config) GetValueArray(key string) []any {
 	var arrayResult []any
-	data := doGetValue(appProperty.ValueDeepMap, key)
+	data := c.getDeepValue(key)
 	err := util.DataToObject(data, &arrayResult)
 	if err != nil {
 		return arrayResult
@@ -870,12 +977,12 @@ func GetValueArray(key string) []any {
 }
 
 func GetValueArrayInt(key string) []int {
-	if nil == appProperty {
-		return nil
-	}
+	return defaultConfig.GetValueArrayInt(key)
+}
 
+func (c *Config) GetValueArrayInt(key string) []int {
 	var arrayResult []int
-	data := doGetValue(appProperty.ValueDeepMap, key)
+	data := c.getDeepValue(key)
 	err := util.DataToObject(data, &arrayResult)
 	if err != nil {
 		return arrayResult
@@ -884,12 +991,12 @@ func GetValueArrayInt(key string) []int {
 }
 
 func GetValueArrayString(key string) []string {
-	if nil == appProperty {
-		return nil
-	}
+	return defaultConfig.GetValueArrayString(key)
+}
 
+func (c *Config) GetValueArrayString(key string) []string {
 	var arrayResult []string
-	data := doGetValue(appProperty.ValueDeepMap, key)
+	data := c.getDeepValue(key)
 	err := util.DataToObject(data, &arrayResult)
 	if err != nil {
 		return arrayResult
@@ -898,10 +1005,35 @@ func GetValueArrayString(key string) []string {
 }
 
 func GetValue(key string) any {
-	if nil == appProperty {
+	return defaultConfig.GetValue(key)
+}
+
+func (c *Config) GetValue(key string) any {
+	return c.getDeepValue(key)
+}
+
+// 读取扁平化的配置值，并解析其中的占位符
+func (c *Config) lookupValue(key string) (any, bool) {
+	property := c.currentProperty()
+	if nil == property {
+		return nil, false
+	}
+	key = property.canonicalKey(key)
+	value, exist := property.ValueMap[key]
+	if !exist {
+		return nil, false
+	}
+	return resolveValueOrRaw(property, key, value), true
+}
+
+// 读取深层的配置值，并解析其中的占位符
+func (c *Config) getDeepValue(key string) any {
+	property := c.currentProperty()
+	if nil == property {
 		return nil
 	}
-	return doGetValue(appProperty.ValueDeepMap, key)
+	key = property.canonicalKey(key)
+	return resolveValueOrRaw(property, key, doGetValue(property.ValueDeepMap, key))
 }
 
 func doGetValue(parentValue any, key string) any {
@@ -925,9 +1057,20 @@ func doGetValue(parentValue any, key string) any {
 	return nil
 }
 
+// ApplicationProperty 配置的快照，生成之后只读，配置变更时候会整体替换为新的快照
 type ApplicationProperty struct {
-	ValueMap     map[string]any
+	// 合并后的扁平化配置
+	ValueMap map[string]any
+	// 合并后的深层配置
 	ValueDeepMap map[string]any
+	// 配置源，按照优先级从低到高排列
+	Sources []*PropertySource
+	// 配置key -> 生效的配置源名字
+	originMap map[string]string
+	// 配置key的规范写法，见canonicalKey
+	canonicalIndex canonicalIndex
+	// 是否为用于查看的配置（见displayProperty），占位符引用的环境变量也需要脱敏
+	display bool
 }
 
 //LoadYamlConfig read fileName from private path fileName,eg:application.yml, and transform it to AConfig
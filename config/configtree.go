@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/simonalong/gole/file"
+)
+
+// 配置树：Kubernetes中ConfigMap、Secret挂载的目录，每个文件为一个配置
+//   - gole.config.tree.locations：配置树的目录，支持数组以及逗号分隔的字符串
+//   - gole.config.tree.interval：检查目录变更的间隔，默认5s
+const configTreeLocationsKey = "gole.config.tree.locations"
+const configTreeIntervalKey = "gole.config.tree.interval"
+
+// Kubernetes更新挂载的目录时候，会将..data这个软链接原子的指向新的版本目录
+const configTreeDataLink = "..data"
+
+// 配置树配置源名字的前缀，比如：configtree:/etc/secrets/
+const configTreeSourcePrefix = "configtree:"
+
+// AddConfigTree 将目录dir作为配置源（类型为configTree，优先级高于配置文件，低于环境变量）：文件的相对路径为key，文件的内容为value（去掉末尾的换行）；
+// 并按照interval检查目录的变更（Kubernetes的..data软链接切换、文件的新增、删除以及修改），有变更则重新读取并发布配置变更事件
+//   - /etc/secrets/gole.datasource.password -> gole.datasource.password
+//   - /etc/config/gole/server/port -> gole.server.port
+//
+// 也可以通过配置添加：gole.config.tree.locations=/etc/secrets/,/etc/config/
+func AddConfigTree(dir string, interval time.Duration) error {
+	return defaultConfig.AddConfigTree(dir, interval)
+}
+
+// AddConfigTree 将目录dir作为配置源，并监听目录的变更，规则同包级别的AddConfigTree
+func (c *Config) AddConfigTree(dir string, interval time.Duration) error {
+	if !file.DirectoryExists(dir) {
+		return fmt.Errorf("配置树的目录[%s]不存在", dir)
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	c.treeLock.Lock()
+	defer c.treeLock.Unlock()
+	if stopChan, exist := c.treeStopChans[dir]; exist {
+		close(stopChan)
+	}
+
+	// 先读取..data的指向再读取文件，读取过程中切换的话下次检查时候会重新读取
+	dataTarget, _ := os.Readlink(filepath.Join(dir, configTreeDataLink))
+	valueMap := readConfigTree(dir)
+	c.SetPropertySource(configTreeSourcePrefix+dir, SourceTypeConfigTree, valueMap)
+
+	stopChan := make(chan struct{})
+	c.treeStopChans[dir] = stopChan
+	go c.watchConfigTree(dir, interval, dataTarget, valueMap, stopChan)
+	return nil
+}
+
+// RemoveConfigTree 停止目录dir的监听，并删除对应的配置源
+func RemoveConfigTree(dir string) {
+	defaultConfig.RemoveConfigTree(dir)
+}
+
+// RemoveConfigTree 停止目录dir的监听，并删除对应的配置源
+func (c *Config) RemoveConfigTree(dir string) {
+	c.treeLock.Lock()
+	if stopChan, exist := c.treeStopChans[dir]; exist {
+		close(stopChan)
+		delete(c.treeStopChans, dir)
+	}
+	c.treeLock.Unlock()
+	c.RemovePropertySource(configTreeSourcePrefix + dir)
+}
+
+// 按照配置加载配置树
+func (c *Config) loadConfigTrees() {
+	locations := propertyPatterns(c.currentProperty(), configTreeLocationsKey)
+	if len(locations) == 0 {
+		return
+	}
+	interval, err := time.ParseDuration(c.GetValueStringDefault(configTreeIntervalKey, "5s"))
+	if err != nil {
+		log.Printf("%s 配置不合法(%v)，使用默认值5s", configTreeIntervalKey, err)
+		interval = 5 * time.Second
+	}
+	for _, location := range locations {
+		if !filepath.IsAbs(location) {
+			location = toAbsPath(location)
+		}
+		if err := c.AddConfigTree(location, interval); err != nil {
+			log.Printf("加载配置树失败，%v", err)
+		}
+	}
+}
+
+// 定时检查目录：有..data软链接的时候只在软链接切换时候重新读取，否则每次都重新读取并和之前的配置对比
+func (c *Config) watchConfigTree(dir string, interval time.Duration, dataTarget string, valueMap map[string]any, stopChan chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			if !file.DirectoryExists(dir) {
+				continue
+			}
+			newTarget, err := os.Readlink(filepath.Join(dir, configTreeDataLink))
+			if err == nil && newTarget == dataTarget {
+				continue
+			}
+			dataTarget = newTarget
+
+			newValueMap := readConfigTree(dir)
+			if reflect.DeepEqual(valueMap, newValueMap) {
+				continue
+			}
+			valueMap = newValueMap
+
+			c.treeLock.Lock()
+			// 在等待锁的时候可能已经被停止
+			select {
+			case <-stopChan:
+				c.treeLock.Unlock()
+				return
+			default:
+			}
+			c.SetPropertySource(configTreeSourcePrefix+dir, SourceTypeConfigTree, valueMap)
+			c.treeLock.Unlock()
+		}
+	}
+}
+
+// 读取目录下的所有文件（跟随软链接，忽略..开头的Kubernetes内部文件以及.开头的隐藏文件），子目录作为key的层级
+func readConfigTree(dir string) map[string]any {
+	valueMap := map[string]any{}
+	readConfigTreeDir(dir, "", valueMap)
+	return valueMap
+}
+
+func readConfigTreeDir(dir string, prefix string, valueMap map[string]any) {
+	entries, err := file.Child(dir)
+	if err != nil {
+		log.Printf("读取配置树的目录[%s]失败，%v", dir, err)
+		return
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		filePath := filepath.Join(dir, entry.Name())
+		key := entry.Name()
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if file.DirectoryExists(filePath) {
+			readConfigTreeDir(filePath, key, valueMap)
+			continue
+		}
+		if !file.FileExists(filePath) {
+			// 指向的文件不存在的软链接
+			continue
+		}
+		valueMap[key] = strings.TrimRight(file.ReadFile(filePath), "\r\n")
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/simonalong/gole/coder"
+	"github.com/simonalong/gole/util"
+)
+
+const encryptPrefix = "ENC("
+const encryptSuffix = ")"
+
+// 加密配置的主密钥，只从命令行或者环境变量中读取，不能放在配置文件中
+//   - gole.config.encrypt.key：主密钥，环境变量为：GOLE_CONFIG_ENCRYPT_KEY
+//   - gole.config.encrypt.file：主密钥文件，环境变量为：GOLE_CONFIG_ENCRYPT_FILE
+const encryptKeyName = "gole.config.encrypt.key"
+const encryptKeyFileName = "gole.config.encrypt.file"
+
+// 查看配置时候，解密后的值用该值代替
+const maskedValue = "******"
+
+var encryptKeyLock sync.Mutex
+var encryptKey string
+
+// SetEncryptKey 设置加密配置的主密钥，优先级高于命令行和环境变量中的配置
+func SetEncryptKey(key string) {
+	encryptKeyLock.Lock()
+	defer encryptKeyLock.Unlock()
+	encryptKey = normalizeEncryptKey(key)
+}
+
+// EncryptValue 使用主密钥（AES-GCM）加密配置值，返回ENC(...)格式，可以直接放到配置文件中
+func EncryptValue(plainText string) (string, error) {
+	key, err := getEncryptKey()
+	if err != nil {
+		return "", err
+	}
+	cipherText, err := coder.AesEncryptGCM(plainText, key)
+	if err != nil {
+		return "", err
+	}
+	return encryptPrefix + cipherText + encryptSuffix, nil
+}
+
+// 判断是否为加密的配置值，比如：ENC(xxxx)
+func isEncryptedValue(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, encryptPrefix) && strings.HasSuffix(value, encryptSuffix)
+}
+
+// 解密ENC(...)格式的配置值
+func decryptValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	key, err := getEncryptKey()
+	if err != nil {
+		return "", err
+	}
+	plainText, err := coder.AesDecryptGCM(value[len(encryptPrefix):len(value)-len(encryptSuffix)], key)
+	if err != nil {
+		return "", fmt.Errorf("加密配置解密失败，%v", err)
+	}
+	return plainText, nil
+}
+
+// 获取主密钥，读取失败不缓存，便于之后再设置
+func getEncryptKey() (string, error) {
+	encryptKeyLock.Lock()
+	defer encryptKeyLock.Unlock()
+	if encryptKey != "" {
+		return encryptKey, nil
+	}
+
+	if key, exist := lookupExternalValue(encryptKeyName); exist && key != "" {
+		encryptKey = normalizeEncryptKey(key)
+		return encryptKey, nil
+	}
+	if keyFile, exist := lookupExternalValue(encryptKeyFileName); exist && keyFile != "" {
+		content, err := os.ReadFile(keyFile)
+		if err != nil {
+			return "", fmt.Errorf("读取主密钥文件[%s]失败，%v", keyFile, err)
+		}
+		encryptKey = normalizeEncryptKey(strings.TrimSpace(string(content)))
+		return encryptKey, nil
+	}
+	return "", errors.New("没有配置加密配置的主密钥，请配置环境变量GOLE_CONFIG_ENCRYPT_KEY或者GOLE_CONFIG_ENCRYPT_FILE")
+}
+
+// 长度为16、24、32的密钥直接使用，其他长度的密钥通过sha256转换为32位
+func normalizeEncryptKey(key string) string {
+	switch len(key) {
+	case 16, 24, 32:
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return string(sum[:])
+}
+
+// 用于查看的配置快照：加密的配置值以及需要脱敏的配置（比如：password、secret、token）替换为******，
+// 这样基于该快照解析出来的值（包括占位符引用的加密配置）都不会包含解密后的值
+func displayProperty(property *ApplicationProperty) *ApplicationProperty {
+	if nil == property {
+		return nil
+	}
+	patterns := maskPatterns(property)
+	valueMap := make(map[string]any, len(property.ValueMap))
+	for key, value := range property.ValueMap {
+		if text, ok := value.(string); (ok && isEncryptedValue(text)) || isMaskedKey(patterns, key) {
+			value = maskedValue
+		}
+		valueMap[key] = value
+	}
+	return &ApplicationProperty{
+		ValueMap:       valueMap,
+		ValueDeepMap:   util.PropertiesMapToDeepMap(valueMap),
+		Sources:        property.Sources,
+		originMap:      property.originMap,
+		canonicalIndex: property.canonicalIndex,
+		display:        true,
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/simonalong/gole/util"
+)
+
+// 需要脱敏的配置，key（忽略大小写以及-、_）中包含其中任意一个则查看时候显示为******；包含*的按照通配符匹配整个key
+//   - gole.endpoint.config.mask.patterns：追加的脱敏规则
+const maskPatternsKey = "gole.endpoint.config.mask.patterns"
+
+// 允许通过/config/update修改的配置，支持通配符，比如：gole.logger.*；没有配置则不限制（protectedKeyPatterns除外）
+const updateAllowKeysKey = "gole.endpoint.config.update.allow-keys"
+
+// 任何时候都不能通过/config/update修改的配置：配置本身的行为（变更记录的文件等）以及端点的安全配置（允许修改的配置、脱敏规则）
+var protectedKeyPatterns = []string{"gole.config.*", "gole.endpoint.*"}
+
+var defaultMaskPatterns = []string{"password", "passwd", "secret", "token", "credential", "private-key", "access-key"}
+
+// UpdateRecord 通过/config/update修改配置的记录，脱敏的配置值显示为******
+type UpdateRecord struct {
+	Time     time.Time `json:"time"`
+	Ip       string    `json:"ip"`
+	Key      string    `json:"key"`
+	OldValue any       `json:"oldValue"`
+	NewValue any       `json:"newValue"`
+	// 是否修改成功，没有在允许修改的配置中则为false
+	Allowed bool `json:"allowed"`
+}
+
+var endpointLock sync.RWMutex
+
+// 配置端点的认证，返回false则拒绝访问
+var endpointAuth func(c *gin.Context) bool
+
+// 配置修改的审计处理，默认打印日志
+var updateAuditHandler = func(record UpdateRecord) {
+	log.Printf("配置变更审计：ip=%s, key=%s, oldValue=%v, newValue=%v, allowed=%v", record.Ip, record.Key, record.OldValue, record.NewValue, record.Allowed)
+}
+
+// SetEndpointAuth 设置配置端点（/config/values、/config/value/{key}、/config/update等）的认证，返回false则响应401
+//
+//	config.SetEndpointAuth(func(c *gin.Context) bool {
+//	    return c.GetHeader("token") == "xxx"
+//	})
+func SetEndpointAuth(auth func(c *gin.Context) bool) {
+	endpointLock.Lock()
+	defer endpointLock.Unlock()
+	endpointAuth = auth
+}
+
+// SetUpdateAuditHandler 设置/config/update修改配置的审计处理，默认打印日志
+func SetUpdateAuditHandler(handler func(record UpdateRecord)) {
+	endpointLock.Lock()
+	defer endpointLock.Unlock()
+	updateAuditHandler = handler
+}
+
+// 校验端点的认证，失败则响应401
+func checkEndpointAuth(c *gin.Context) bool {
+	endpointLock.RLock()
+	auth := endpointAuth
+	endpointLock.RUnlock()
+	if auth == nil || auth(c) {
+		return true
+	}
+	c.Data(http.StatusUnauthorized, "application/json; charset=utf-8", []byte(util.ObjectToJson(map[string]any{"code": http.StatusUnauthorized, "message": "没有权限访问配置"})))
+	c.Abort()
+	return false
+}
+
+func auditUpdate(record UpdateRecord) {
+	endpointLock.RLock()
+	handler := updateAuditHandler
+	endpointLock.RUnlock()
+	if handler != nil {
+		handler(record)
+	}
+}
+
+// 配置是否允许通过/config/update修改：只允许allow-keys中配置的key，没有配置则都不允许
+func isUpdateAllowed(property *ApplicationProperty, key string) bool {
+	for _, pattern := range protectedKeyPatterns {
+		if matchKeyPattern(pattern, key) {
+			return false
+		}
+	}
+	for _, pattern := range propertyPatterns(property, updateAllowKeysKey) {
+		if matchKeyPattern(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// 配置是否需要脱敏
+func isMaskedKey(patterns []string, key string) bool {
+	relaxed := relaxedKey(key)
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "*") {
+			if matchKeyPattern(pattern, key) {
+				return true
+			}
+		} else if strings.Contains(relaxed, relaxedKey(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// 脱敏规则：默认规则 + 配置的规则
+func maskPatterns(property *ApplicationProperty) []string {
+	return append(append([]string{}, defaultMaskPatterns...), propertyPatterns(property, maskPatternsKey)...)
+}
+
+// 通配符匹配，比如：gole.logger.*匹配gole.logger.level以及gole.logger.group.orm.level
+func matchKeyPattern(pattern, key string) bool {
+	pattern, key = relaxedKey(pattern), relaxedKey(key)
+	if strings.HasSuffix(pattern, ".*") && strings.HasPrefix(key, strings.TrimSuffix(pattern, "*")) {
+		return true
+	}
+	matched, _ := path.Match(pattern, key)
+	return matched
+}
+
+// 读取规则列表，支持数组以及逗号分隔的字符串
+func propertyPatterns(property *ApplicationProperty, key string) []string {
+	if nil == property {
+		return nil
+	}
+	var patterns []string
+	switch value := doGetValue(property.ValueDeepMap, key).(type) {
+	case []any:
+		for _, item := range value {
+			patterns = append(patterns, splitProfiles(util.ToString(item))...)
+		}
+	case nil:
+	default:
+		patterns = splitProfiles(util.ToString(value))
+	}
+	return patterns
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 环境变量中gole配置的前缀，比如：GOLE_SERVER_PORT -> gole.server.port
+const envGolePrefix = "GOLE_"
+
+// 命令行参数的前缀，比如：--gole.server.port=9090
+const argPrefix = "--"
+
+var envNamePattern = regexp.MustCompile("^[A-Z0-9_]+$")
+var keyIndexPattern = regexp.MustCompile("\\[(\\d+)\\]")
+
+// 读取环境变量中的配置，优先级高于配置文件，支持如下格式
+//   - GOLE_SERVER_PORT=9090 -> gole.server.port
+//   - GOLE_KAFKA_ADDRS_0=xxx -> gole.kafka.addrs[0]
+//   - gole.server.port=9090：直接使用原始key（兼容之前的写法）
+//
+// 非GOLE_开头的环境变量，只有在宽松匹配到配置文件中已有的key时候才会生效，比如：APP_NAME -> app.name
+func environmentValues(property *ApplicationProperty, env []string) map[string]any {
+	keyIndex := relaxedKeyIndex(property)
+	valueMap := map[string]any{}
+	for _, kv := range env {
+		index := strings.Index(kv, "=")
+		if index <= 0 {
+			continue
+		}
+		name, value := kv[:index], kv[index+1:]
+
+		if strings.Contains(name, ".") {
+			valueMap[name] = value
+			continue
+		}
+		if !envNamePattern.MatchString(name) {
+			continue
+		}
+
+		if key, exist := keyIndex[relaxedKey(envNameToKey(name))]; exist {
+			valueMap[key] = value
+		} else if strings.HasPrefix(name, envGolePrefix) {
+			valueMap[envNameToKey(name)] = value
+		}
+	}
+	return valueMap
+}
+
+// 读取命令行中的配置，优先级高于环境变量，格式：--gole.server.port=9090
+func commandLineValues(property *ApplicationProperty, args []string) map[string]any {
+	keyIndex := relaxedKeyIndex(property)
+	valueMap := map[string]any{}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, argPrefix) {
+			continue
+		}
+		arg = arg[len(argPrefix):]
+		index := strings.Index(arg, "=")
+		if index <= 0 {
+			continue
+		}
+		key, value := arg[:index], arg[index+1:]
+		if existKey, exist := keyIndex[relaxedKey(key)]; exist {
+			key = existKey
+		}
+		valueMap[key] = value
+	}
+	return valueMap
+}
+
+// 按照优先级读取外部配置：命令行 > 环境变量；用于配置文件加载前就需要的key，比如：gole.profiles.active
+func lookupExternalValue(key string) (string, bool) {
+	for _, arg := range os.Args[1:] {
+		if strings.HasPrefix(arg, argPrefix+key+"=") {
+			return arg[len(argPrefix+key+"="):], true
+		}
+	}
+
+	if value, exist := os.LookupEnv(key); exist {
+		return value, true
+	}
+	return os.LookupEnv(keyToEnvName(key))
+}
+
+// GOLE_KAFKA_ADDRS_0 -> gole.kafka.addrs[0]
+func envNameToKey(name string) string {
+	var builder strings.Builder
+	for index, word := range strings.Split(strings.ToLower(name), "_") {
+		if word == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(word); err == nil && index > 0 {
+			builder.WriteString("[" + word + "]")
+			continue
+		}
+		if builder.Len() > 0 {
+			builder.WriteString(".")
+		}
+		builder.WriteString(word)
+	}
+	return builder.String()
+}
+
+// gole.kafka.addrs[0] -> GOLE_KAFKA_ADDRS_0
+func keyToEnvName(key string) string {
+	key = keyIndexPattern.ReplaceAllString(key, ".$1")
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "").Replace(key))
+}
+
+// 已有配置的宽松匹配索引：宽松key -> 原始key
+func relaxedKeyIndex(property *ApplicationProperty) map[string]string {
+	keyIndex := map[string]string{}
+	if nil == property {
+		return keyIndex
+	}
+	for key := range property.ValueMap {
+		keyIndex[relaxedKey(key)] = key
+	}
+	return keyIndex
+}
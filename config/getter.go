@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// KeyNotFoundError 配置不存在
+type KeyNotFoundError struct {
+	Key string
+}
+
+func (e *KeyNotFoundError) Error() string {
+	return fmt.Sprintf("配置[%s]不存在", e.Key)
+}
+
+// ConvertError 配置值无法转换为目标类型；对象、数组等类型的配置中每个不合法的key都在Fields中
+type ConvertError struct {
+	Key    string
+	Value  any
+	Type   reflect.Type
+	Fields []BindFieldError
+}
+
+func (e *ConvertError) Error() string {
+	if len(e.Fields) == 1 && e.Fields[0].Key == e.Key {
+		// 字段的异常信息中已经包含了值，比如：配置[gole.server.port]的值[abc]无法转换为int类型
+		return fmt.Sprintf("配置[%s]的%s", e.Key, e.Fields[0].ErrMsg)
+	}
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("配置[%s]无法转换为%v类型：", e.Key, e.Type))
+	for _, field := range e.Fields {
+		builder.WriteString(fmt.Sprintf("\n  - %s：%s", field.Key, field.ErrMsg))
+	}
+	return builder.String()
+}
+
+// Lookup 读取配置值（解析占位符以及加密配置），并返回配置是否存在；key可以为叶子节点，也可以为非叶子节点
+func Lookup(key string) (any, bool) {
+	return defaultConfig.Lookup(key)
+}
+
+// Lookup 读取配置值（解析占位符以及加密配置），并返回配置是否存在；key可以为叶子节点，也可以为非叶子节点
+func (c *Config) Lookup(key string) (any, bool) {
+	value, exist, err := lookupResolvedValue(c.currentProperty(), key)
+	if err != nil || !exist {
+		return nil, false
+	}
+	return value, true
+}
+
+// Get 读取配置并转换为类型T；配置不存在返回KeyNotFoundError，转换失败返回ConvertError（占位符解析失败返回PlaceholderError）
+//
+// 类型转换规则同Bind，比如：
+//
+//	port, err := config.Get[int]("gole.server.port")
+//	timeout, err := config.Get[time.Duration]("gole.redis.timeout")
+//	addrs, err := config.Get[[]string]("gole.kafka.addrs")
+func Get[T any](key string) (T, error) {
+	return GetFrom[T](defaultConfig, key)
+}
+
+// GetFrom 读取配置实例c中的配置并转换为类型T，规则同Get
+func GetFrom[T any](c *Config, key string) (T, error) {
+	var result T
+	value, exist, err := lookupResolvedValue(c.currentProperty(), key)
+	if err != nil {
+		return result, err
+	}
+	if !exist {
+		return result, &KeyNotFoundError{Key: key}
+	}
+
+	binder := &valueBinder{}
+	binder.bind(key, value, reflect.ValueOf(&result).Elem())
+	if len(binder.errs) > 0 {
+		var empty T
+		return empty, &ConvertError{Key: key, Value: value, Type: reflect.TypeOf(&result).Elem(), Fields: binder.errs}
+	}
+	return result, nil
+}
+
+// GetDuration 读取时间间隔：3s、500ms、1h30m等，纯数字表示毫秒
+func GetDuration(key string) (time.Duration, error) {
+	return defaultConfig.GetDuration(key)
+}
+
+// GetDuration 读取时间间隔：3s、500ms、1h30m等，纯数字表示毫秒
+func (c *Config) GetDuration(key string) (time.Duration, error) {
+	return GetFrom[time.Duration](c, key)
+}
+
+// GetByteSize 读取字节大小：1024、512B、10KB、10MB、1GB等
+func GetByteSize(key string) (ByteSize, error) {
+	return defaultConfig.GetByteSize(key)
+}
+
+// GetByteSize 读取字节大小：1024、512B、10KB、10MB、1GB等
+func (c *Config) GetByteSize(key string) (ByteSize, error) {
+	return GetFrom[ByteSize](c, key)
+}
+
+// GetTime 读取时间：支持RFC3339、2006-01-02 15:04:05、2006-01-02等格式，没有时区的按照本地时区解析
+func GetTime(key string) (time.Time, error) {
+	return defaultConfig.GetTime(key)
+}
+
+// GetTime 读取时间：支持RFC3339、2006-01-02 15:04:05、2006-01-02等格式，没有时区的按照本地时区解析
+func (c *Config) GetTime(key string) (time.Time, error) {
+	return GetFrom[time.Time](c, key)
+}
+
+// GetMap 读取非叶子节点的配置
+func GetMap(key string) (map[string]any, error) {
+	return defaultConfig.GetMap(key)
+}
+
+// GetMap 读取非叶子节点的配置
+func (c *Config) GetMap(key string) (map[string]any, error) {
+	return GetFrom[map[string]any](c, key)
+}
+
+// GetSlice 读取数组配置，每个元素转换为类型T；字符串按照逗号分隔
+func GetSlice[T any](key string) ([]T, error) {
+	return GetFrom[[]T](defaultConfig, key)
+}
+
+// GetSliceFrom 读取配置实例c中的数组配置，规则同GetSlice
+func GetSliceFrom[T any](c *Config, key string) ([]T, error) {
+	return GetFrom[[]T](c, key)
+}
+
+// 读取解析后的配置值：先按照深层结构读取，读取不到的（比如：带有数组下标的key）再读取扁平化的配置
+func lookupResolvedValue(property *ApplicationProperty, key string) (any, bool, error) {
+	if nil == property {
+		return nil, false, nil
+	}
+	key = property.canonicalKey(key)
+	value := doGetValue(property.ValueDeepMap, key)
+	if nil == value {
+		flatValue, exist := property.ValueMap[key]
+		if !exist || nil == flatValue {
+			return nil, false, nil
+		}
+		value = flatValue
+	}
+	resolved, err := resolveValue(property, key, value)
+	if err != nil {
+		return nil, true, err
+	}
+	return resolved, true, nil
+}
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/simonalong/gole/util"
+)
+
+// 运行时配置变更历史的配置
+//   - gole.config.history.max-size：保留的变更记录数，默认100
+//   - gole.config.history.file：变更记录持久化的文件，不配置则不持久化
+const historyMaxSizeKey = "gole.config.history.max-size"
+const historyFileKey = "gole.config.history.file"
+const defaultHistoryMaxSize = 100
+
+// 变更的来源
+const (
+	// ChangeSourceCode 代码中调用SetValue、AppendValue
+	ChangeSourceCode = "code"
+	// ChangeSourceEndpoint 通过/config/update修改，来源中带有调用方的ip，比如：endpoint:127.0.0.1
+	ChangeSourceEndpoint = "endpoint"
+	// ChangeSourceRollback 通过Rollback回滚
+	ChangeSourceRollback = "rollback"
+)
+
+// ChangeRecord 运行时配置的变更记录；一次修改（比如：SetValue一个对象）涉及多个key时候，多条记录的版本号相同
+type ChangeRecord struct {
+	Version int64  `json:"version"`
+	Key     string `json:"key"`
+	// 修改前后生效的值，需要脱敏的配置显示为******；nil表示配置不存在
+	OldValue any       `json:"oldValue"`
+	NewValue any       `json:"newValue"`
+	Source   string    `json:"source"`
+	Time     time.Time `json:"time"`
+
+	// 运行时配置源中修改前的值，用于回滚；从文件中恢复的记录没有该值，不能回滚
+	rollback *runtimeValue
+}
+
+type runtimeValue struct {
+	value any
+	exist bool
+}
+
+// GetChangeHistory 获取运行时配置的变更记录，按照版本从旧到新排列
+func GetChangeHistory() []ChangeRecord {
+	return defaultConfig.GetChangeHistory()
+}
+
+// GetChangeHistory 获取运行时配置的变更记录，按照版本从旧到新排列
+func (c *Config) GetChangeHistory() []ChangeRecord {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	return append([]ChangeRecord{}, c.changeHistory...)
+}
+
+// Rollback 将运行时配置回滚到版本version修改之前的值，即撤销版本version以及之后的所有修改，并发布配置变更事件；
+// 回滚本身也会作为一个新的版本记录下来
+func Rollback(version int64) error {
+	return defaultConfig.Rollback(version)
+}
+
+// Rollback 将运行时配置回滚到版本version修改之前的值，即撤销版本version以及之后的所有修改，并发布配置变更事件
+func (c *Config) Rollback(version int64) error {
+	var rollbackErr error
+	oldProperty, newProperty, changed := c.updateRuntimeValues(ChangeSourceRollback, func(_ *ApplicationProperty, valueMap map[string]any) bool {
+		c.historyLock.Lock()
+		defer c.historyLock.Unlock()
+		changeHistory := c.changeHistory
+		if len(changeHistory) == 0 || version < changeHistory[0].Version || version > c.lastVersion {
+			rollbackErr = fmt.Errorf("版本[%d]不在变更记录中", version)
+			return false
+		}
+		for index := len(changeHistory) - 1; index >= 0 && changeHistory[index].Version >= version; index-- {
+			if changeHistory[index].rollback == nil {
+				rollbackErr = fmt.Errorf("版本[%d]为重启之前的变更，不能回滚", changeHistory[index].Version)
+				return false
+			}
+		}
+		for index := len(changeHistory) - 1; index >= 0 && changeHistory[index].Version >= version; index-- {
+			record := changeHistory[index]
+			if record.rollback.exist {
+				valueMap[record.Key] = record.rollback.value
+			} else {
+				delete(valueMap, record.Key)
+			}
+		}
+		return true
+	})
+	if rollbackErr != nil {
+		return rollbackErr
+	}
+	if changed {
+		c.publishChangeEvents(oldProperty, newProperty)
+	}
+	return nil
+}
+
+// GetConfigHistory 查看运行时配置的变更记录
+func GetConfigHistory(c *gin.Context) {
+	defaultConfig.GetConfigHistory(c)
+}
+
+// GetConfigHistory 查看运行时配置的变更记录
+func (c *Config) GetConfigHistory(ctx *gin.Context) {
+	if !checkEndpointAuth(ctx) {
+		return
+	}
+	ctx.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(c.GetChangeHistory())))
+}
+
+// 修改运行时配置源，并记录运行时配置源中变更的key
+func (c *Config) updateRuntimeValues(source string, update func(property *ApplicationProperty, valueMap map[string]any) bool) (*ApplicationProperty, *ApplicationProperty, bool) {
+	var save func()
+	oldProperty, newProperty, changed := c.updateRuntimeSource(func(property *ApplicationProperty, valueMap map[string]any) bool {
+		before := make(map[string]any, len(valueMap))
+		for key, value := range valueMap {
+			before[key] = value
+		}
+		if !update(property, valueMap) {
+			return false
+		}
+		save = c.recordChanges(property, before, valueMap, source)
+		return true
+	})
+	// 文件的读写不在锁内
+	if save != nil {
+		save()
+	}
+	return oldProperty, newProperty, changed
+}
+
+// 对比运行时配置源修改前后的值，生成一个新版本的变更记录；返回持久化变更记录的函数，没有变更则返回nil
+func (c *Config) recordChanges(property *ApplicationProperty, before, after map[string]any, source string) func() {
+	keys := map[string]bool{}
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	var records []ChangeRecord
+	now := time.Now()
+	for key := range keys {
+		oldRuntime, oldExist := before[key]
+		newRuntime, newExist := after[key]
+		if oldExist == newExist && reflect.DeepEqual(oldRuntime, newRuntime) {
+			continue
+		}
+		var oldValue, newValue any
+		if nil != property {
+			oldValue = property.ValueMap[key]
+		}
+		if newExist {
+			newValue = newRuntime
+		}
+		records = append(records, ChangeRecord{
+			Key:      key,
+			OldValue: maskValue(property, key, oldValue),
+			NewValue: maskValue(property, key, newValue),
+			Source:   source,
+			Time:     now,
+			rollback: &runtimeValue{value: oldRuntime, exist: oldExist},
+		})
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	c.lastVersion++
+	for index := range records {
+		records[index].Version = c.lastVersion
+	}
+	c.changeHistory = append(c.changeHistory, records...)
+	if maxSize := historyMaxSize(property); len(c.changeHistory) > maxSize {
+		c.changeHistory = append([]ChangeRecord{}, c.changeHistory[len(c.changeHistory)-maxSize:]...)
+	}
+
+	filePath := historyFile(property)
+	if filePath == "" {
+		return nil
+	}
+	history, version := append([]ChangeRecord{}, c.changeHistory...), c.lastVersion
+	return func() {
+		c.saveHistory(filePath, history, version)
+	}
+}
+
+func historyMaxSize(property *ApplicationProperty) int {
+	if nil != property {
+		if value, exist := property.ValueMap[historyMaxSizeKey]; exist {
+			if maxSize := util.ToInt(value); maxSize > 0 {
+				return maxSize
+			}
+		}
+	}
+	return defaultHistoryMaxSize
+}
+
+func historyFile(property *ApplicationProperty) string {
+	if nil == property {
+		return ""
+	}
+	if value, exist := property.ValueMap[historyFileKey]; exist {
+		return util.ToString(value)
+	}
+	return ""
+}
+
+// 持久化变更记录；多次修改并发保存时候，只保存版本更新的
+func (c *Config) saveHistory(filePath string, history []ChangeRecord, version int64) {
+	c.historySaveLock.Lock()
+	defer c.historySaveLock.Unlock()
+	if version <= c.savedVersion {
+		return
+	}
+	content, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		log.Printf("保存配置变更记录[%s]失败，%v", filePath, err)
+		return
+	}
+	if err := os.WriteFile(filePath, content, 0600); err != nil {
+		log.Printf("保存配置变更记录[%s]失败，%v", filePath, err)
+		return
+	}
+	c.savedVersion = version
+}
+
+// 加载配置时候从文件中恢复变更记录，恢复的记录只能查看，不能回滚
+func (c *Config) loadHistory() {
+	filePath := historyFile(c.currentProperty())
+	if filePath == "" {
+		return
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+	var records []ChangeRecord
+	if err := json.Unmarshal(content, &records); err != nil {
+		log.Printf("读取配置变更记录[%s]失败，%v", filePath, err)
+		return
+	}
+
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	c.changeHistory = records
+	for _, record := range records {
+		if record.Version > c.lastVersion {
+			c.lastVersion = record.Version
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/simonalong/gole/file"
+	"github.com/simonalong/gole/util"
+)
+
+// 配置文件中导入其他配置文件，比如：gole.config.import: [shared/db.yml, optional:secrets.properties, dir:/etc/app/conf.d/]
+const importKey = "gole.config.import"
+
+// 导入的文件不存在时候忽略
+const importOptionalPrefix = "optional:"
+
+// 导入目录下所有支持的配置文件（按照文件名排序，不包括子目录）
+const importDirPrefix = "dir:"
+
+// 读取配置文件以及其通过gole.config.import导入的文件（递归），导入的文件紧跟在导入它的文件之后，优先级高于导入它的文件；
+// chain为导入链路，用于循环导入的检测
+func (c *Config) readFileSources(filePath string, sourceType string, parser func(string) (map[string]any, error), chain []string) []*PropertySource {
+	valueMap, ok := readFileSource(filePath, parser)
+	if !ok {
+		return nil
+	}
+	locations := takeImportLocations(valueMap)
+	sources := []*PropertySource{{Name: filePath, Type: sourceType, ValueMap: valueMap, parser: parser}}
+
+	chain = appendChain(chain, absFilePath(filePath))
+	for _, location := range locations {
+		sources = append(sources, c.readImportSources(filePath, location, sourceType, chain)...)
+	}
+	return sources
+}
+
+// 读取导入的配置，相对路径相对于导入它的文件所在的目录
+func (c *Config) readImportSources(importingFile string, location string, sourceType string, chain []string) []*PropertySource {
+	optional := strings.HasPrefix(location, importOptionalPrefix)
+	location = strings.TrimSpace(strings.TrimPrefix(location, importOptionalPrefix))
+	isDir := strings.HasPrefix(location, importDirPrefix)
+	location = strings.TrimSpace(strings.TrimPrefix(location, importDirPrefix))
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(filepath.Dir(importingFile), location)
+	}
+
+	if !isDir {
+		return c.readImportFile(location, sourceType, optional, chain)
+	}
+
+	entries, err := os.ReadDir(location)
+	if err != nil {
+		if !optional {
+			log.Printf("导入的配置目录[%s]读取失败，%v", location, err)
+		}
+		return nil
+	}
+	var sources []*PropertySource
+	for _, entry := range entries {
+		if entry.IsDir() || c.fileParser(entry.Name()) == nil {
+			continue
+		}
+		sources = append(sources, c.readImportFile(filepath.Join(location, entry.Name()), sourceType, optional, chain)...)
+	}
+	return sources
+}
+
+func (c *Config) readImportFile(filePath string, sourceType string, optional bool, chain []string) []*PropertySource {
+	if containsKey(chain, absFilePath(filePath)) {
+		log.Printf("配置文件存在循环导入，忽略：%s", strings.Join(appendChain(chain, absFilePath(filePath)), " -> "))
+		return nil
+	}
+	parser := c.fileParser(filePath)
+	if parser == nil {
+		log.Printf("导入的配置文件[%s]格式不支持", filePath)
+		return nil
+	}
+	if !file.FileExists(filePath) {
+		if !optional {
+			log.Printf("导入的配置文件[%s]不存在", filePath)
+		}
+		return nil
+	}
+	return c.readFileSources(filePath, sourceType, parser, chain)
+}
+
+// 取出配置中导入的文件，并从配置中删除；支持数组以及逗号分隔的字符串
+func takeImportLocations(valueMap map[string]any) []string {
+	var locations []string
+	if value, exist := valueMap[importKey]; exist {
+		if reflect.ValueOf(value).Kind() == reflect.Slice {
+			for _, item := range value.([]any) {
+				locations = append(locations, util.ToString(item))
+			}
+		} else {
+			locations = append(locations, strings.Split(util.ToString(value), ",")...)
+		}
+		delete(valueMap, importKey)
+	}
+	for index := 0; ; index++ {
+		key := importKey + "[" + util.ToString(index) + "]"
+		value, exist := valueMap[key]
+		if !exist {
+			break
+		}
+		locations = append(locations, util.ToString(value))
+		delete(valueMap, key)
+	}
+
+	var result []string
+	for _, location := range locations {
+		if location = strings.TrimSpace(location); location != "" {
+			result = append(result, location)
+		}
+	}
+	return result
+}
+
+func absFilePath(filePath string) string {
+	if absPath, err := filepath.Abs(filePath); err == nil {
+		return absPath
+	}
+	return filePath
+}
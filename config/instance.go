@@ -0,0 +1,155 @@
+package config
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/simonalong/gole/listener"
+)
+
+// Config 配置实例，包含配置源、激活的profile、配置文件的监听以及运行时配置的变更记录等；
+// 包级别的函数（GetValueXxx、SetValue、LoadConfig等）都是默认实例的代理，一个进程中需要多份独立配置（比如：测试、多个逻辑应用）时候可以通过New创建
+//
+//	cfg := config.New()
+//	cfg.LoadConfigFromAbsPath("/etc/app2/")
+//	port := cfg.GetValueInt("gole.server.port")
+type Config struct {
+	// 当前配置的快照（*ApplicationProperty），快照生成后只读；每次变更都会生成新的快照并原子替换
+	snapshot atomic.Value
+	// 配置变更的写锁，保证多个写操作串行
+	writeLock sync.Mutex
+
+	loadLock sync.Mutex
+	loaded   bool
+	// 是否存在配置文件
+	exist atomic.Value
+
+	// 当前激活的profile（[]string），按照优先级从低到高排列
+	activeProfiles atomic.Value
+
+	// 配置文件的监听
+	watchLock     sync.Mutex
+	watchStopChan chan struct{}
+
+	// 运行时配置的变更记录
+	historyLock   sync.Mutex
+	changeHistory []ChangeRecord
+	lastVersion   int64
+	// 变更记录持久化的锁以及已经保存的版本
+	historySaveLock sync.Mutex
+	savedVersion    int64
+
+	// 配置树的监听：目录 -> 停止监听的chan
+	treeLock      sync.Mutex
+	treeStopChans map[string]chan struct{}
+
+	// 已经提示过的未知以及废弃的配置
+	warnLock   sync.Mutex
+	warnedKeys map[string]bool
+
+	// 配置变更的监听
+	listenerLock   sync.Mutex
+	listeners      []changeListener
+	lastListenerId int64
+}
+
+type changeListener struct {
+	id     int64
+	notify func(event listener.ConfigChangeEvent)
+}
+
+// 默认实例，包级别的函数都使用该实例
+var defaultConfig = New()
+
+// New 创建一个空的配置实例
+func New() *Config {
+	return &Config{warnedKeys: map[string]bool{}, treeStopChans: map[string]chan struct{}{}}
+}
+
+// Default 获取默认的配置实例
+func Default() *Config {
+	return defaultConfig
+}
+
+// AddChangeListener 添加配置变更的监听，返回移除该监听的函数；默认实例的配置变更同时也会发布到listener中（listener.EventOfConfigChange），其他实例的只通知这里添加的监听
+func (c *Config) AddChangeListener(notify func(event listener.ConfigChangeEvent)) (remove func()) {
+	c.listenerLock.Lock()
+	defer c.listenerLock.Unlock()
+	c.lastListenerId++
+	id := c.lastListenerId
+	c.listeners = append(c.listeners, changeListener{id: id, notify: notify})
+	return func() {
+		c.removeChangeListener(id)
+	}
+}
+
+func (c *Config) removeChangeListener(id int64) {
+	c.listenerLock.Lock()
+	defer c.listenerLock.Unlock()
+	for index, item := range c.listeners {
+		if item.id == id {
+			c.listeners = append(c.listeners[:index:index], c.listeners[index+1:]...)
+			return
+		}
+	}
+}
+
+// 发布配置变更事件
+func (c *Config) publishEvent(event listener.ConfigChangeEvent) {
+	c.listenerLock.Lock()
+	listeners := c.listeners
+	c.listenerLock.Unlock()
+	for _, item := range listeners {
+		item.notify(event)
+	}
+	if c == defaultConfig {
+		listener.PublishEvent(event)
+	}
+}
+
+// WithOverrides 用于测试：将overrides作为运行时配置覆盖到默认实例中，测试结束时候恢复之前的配置（包括变更记录），并发布对应的配置变更事件
+//
+//	func TestXxx(t *testing.T) {
+//	    config.WithOverrides(t, map[string]any{"gole.server.port": 8081})
+//	}
+func WithOverrides(t interface{ Cleanup(func()) }, overrides map[string]any) {
+	defaultConfig.WithOverrides(t, overrides)
+}
+
+// WithOverrides 用于测试：将overrides作为运行时配置覆盖到当前实例中，测试结束时候恢复之前的配置（包括变更记录），并发布对应的配置变更事件；
+// overrides的value可以为对象、数组，会展开为扁平化的配置
+func (c *Config) WithOverrides(t interface{ Cleanup(func()) }, overrides map[string]any) {
+	c.historyLock.Lock()
+	history := append([]ChangeRecord{}, c.changeHistory...)
+	lastVersion := c.lastVersion
+	c.historyLock.Unlock()
+
+	oldProperty, newProperty, _ := c.updateRuntimeSource(func(property *ApplicationProperty, valueMap map[string]any) bool {
+		newValues := map[string]any{}
+		for key, value := range overrides {
+			_, _ = parseProperties(key, value, newValues)
+		}
+		mergeValues(valueMap, newValues, listMergeStrategies(property.ValueMap), property.canonicalIndex)
+		return true
+	})
+	c.publishChangeEvents(oldProperty, newProperty)
+
+	t.Cleanup(func() {
+		current, restored := c.updatePropertySources(func([]*PropertySource) []*PropertySource {
+			if nil == oldProperty {
+				return nil
+			}
+			return append([]*PropertySource{}, oldProperty.Sources...)
+		})
+		c.historyLock.Lock()
+		c.changeHistory = history
+		c.lastVersion = lastVersion
+		c.historyLock.Unlock()
+		c.historySaveLock.Lock()
+		if c.savedVersion > lastVersion {
+			c.savedVersion = lastVersion
+		}
+		c.historySaveLock.Unlock()
+		c.publishChangeEvents(current, restored)
+	})
+}
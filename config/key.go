@@ -0,0 +1,178 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 配置key的宽松匹配（同spring的relaxed binding）：忽略大小写、中划线和下划线，数组下标与普通层级等同；
+// 比如：gole.redis.pool-size、gole.redis.poolSize、gole.redis.pool_size、GOLE_REDIS_POOL_SIZE 都对应同一个配置。
+// 合并配置源、读取配置、绑定以及修改配置时候都通过这里统一转换为规范的写法
+
+// 宽松匹配的key：忽略大小写、中划线和下划线，数组下标与普通层级等同
+// 比如：gole.kafka.addrs[0]、gole.kafka.addrs.0 -> gole.kafka.addrs.0；gole.datasource.driver-name -> gole.datasource.drivername
+func relaxedKey(key string) string {
+	return walkRelaxedKey(key, nil)
+}
+
+// 转换为宽松匹配的key，并在每一层的结尾（"."、"[n]"之前以及key的结尾）回调visit：end为原始key中的位置，relaxed为到该位置的宽松key
+func walkRelaxedKey(key string, visit func(end int, relaxed string)) string {
+	var builder strings.Builder
+	builder.Grow(len(key))
+	for index := 0; index < len(key); index++ {
+		ch := key[index]
+		switch {
+		case ch == '.':
+			if visit != nil && index > 0 {
+				visit(index, builder.String())
+			}
+			builder.WriteByte('.')
+		case ch == '[':
+			end := index + 1
+			for end < len(key) && key[end] >= '0' && key[end] <= '9' {
+				end++
+			}
+			if end == index+1 || end >= len(key) || key[end] != ']' {
+				builder.WriteByte(ch)
+				continue
+			}
+			if visit != nil && index > 0 {
+				visit(index, builder.String())
+			}
+			builder.WriteByte('.')
+			builder.WriteString(key[index+1 : end])
+			index = end
+		case ch == '-' || ch == '_':
+		case ch >= 'A' && ch <= 'Z':
+			builder.WriteByte(ch + 'a' - 'A')
+		case ch < utf8.RuneSelf:
+			builder.WriteByte(ch)
+		default:
+			r, size := utf8.DecodeRuneInString(key[index:])
+			builder.WriteRune(unicode.ToLower(r))
+			index += size - 1
+		}
+	}
+	relaxed := builder.String()
+	if visit != nil && len(key) > 0 {
+		visit(len(key), relaxed)
+	}
+	return relaxed
+}
+
+// 规范写法的索引：宽松的key（包括每一层的前缀） -> 规范的写法；规范的写法为第一次出现（优先级最低的配置源中）的写法，
+// 逐层规范，比如：已有gole.dataSource.url，则gole.datasource.user-name -> gole.dataSource.user-name
+type canonicalIndex map[string]string
+
+// 转换为规范的写法：从第一层开始逐层匹配索引，没有匹配到的部分保留原始写法
+func (index canonicalIndex) canonical(key string) string {
+	matchedEnd, canonical, stopped := 0, "", false
+	walkRelaxedKey(key, func(end int, relaxed string) {
+		if stopped {
+			return
+		}
+		if value, exist := index[relaxed]; exist {
+			matchedEnd, canonical = end, value
+			return
+		}
+		stopped = true
+	})
+	if matchedEnd == 0 {
+		return key
+	}
+	return canonical + key[matchedEnd:]
+}
+
+// 添加到索引中，返回规范的写法
+func (index canonicalIndex) add(key string) string {
+	key = index.canonical(key)
+	walkRelaxedKey(key, func(end int, relaxed string) {
+		if _, exist := index[relaxed]; !exist {
+			index[relaxed] = key[:end]
+		}
+	})
+	return key
+}
+
+// 配置key的规范写法，没有对应的配置则返回原始的key
+func (property *ApplicationProperty) canonicalKey(key string) string {
+	if nil == property || nil == property.canonicalIndex {
+		return key
+	}
+	if _, exist := property.ValueMap[key]; exist {
+		return key
+	}
+	return property.canonicalIndex.canonical(key)
+}
+
+// 在配置源中查找key对应的值：先精确匹配，后宽松匹配
+func lookupRelaxedValue(valueMap map[string]any, key string) (any, bool) {
+	if value, exist := valueMap[key]; exist {
+		return value, true
+	}
+	relaxed := relaxedKey(key)
+	for sourceKey, value := range valueMap {
+		if relaxedKey(sourceKey) == relaxed {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// 将配置中的key按照宽松匹配对齐到结构体的属性名（规则同Bind：先yaml、json标签，后属性名），使GetValueObject与Bind的匹配结果一致
+func alignFieldKeys(data any, targetType reflect.Type) any {
+	for targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	switch targetType.Kind() {
+	case reflect.Struct:
+		dataMap, ok := data.(map[string]any)
+		if !ok || targetType == timeType {
+			return data
+		}
+		result := make(map[string]any, len(dataMap))
+		for key, value := range dataMap {
+			result[key] = value
+		}
+		for index := 0; index < targetType.NumField(); index++ {
+			field := targetType.Field(index)
+			if !field.IsExported() || field.Anonymous {
+				continue
+			}
+			name, inline := fieldKeyName(field)
+			if name == "-" || inline {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if _, value, exist := lookupField(dataMap, name); exist {
+				result[field.Name] = alignFieldKeys(value, field.Type)
+			}
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		items, ok := data.([]any)
+		if !ok {
+			return data
+		}
+		result := make([]any, len(items))
+		for index, item := range items {
+			result[index] = alignFieldKeys(item, targetType.Elem())
+		}
+		return result
+	case reflect.Map:
+		dataMap, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+		result := make(map[string]any, len(dataMap))
+		for key, value := range dataMap {
+			result[key] = alignFieldKeys(value, targetType.Elem())
+		}
+		return result
+	}
+	return data
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/simonalong/gole/util"
+)
+
+// 数组的合并方式，按照数组的key配置，没有配置的数组默认为replace，比如：
+//
+//	gole.config.merge.lists.gole.kafka.addrs=append
+//	gole.config.merge.lists.gole.datasource.list=merge:name
+const listMergeKeyPrefix = "gole.config.merge.lists."
+
+// 数组的合并方式
+const (
+	// ListMergeReplace 优先级高的配置源中的数组整体替换优先级低的数组，多余的下标会被删除（默认）
+	ListMergeReplace = "replace"
+	// ListMergeAppend 优先级高的配置源中的数组追加到优先级低的数组后面
+	ListMergeAppend = "append"
+	// ListMergeByKey 按照元素的字段合并，格式为merge:字段名：字段值相同的元素合并其中的字段，其他的追加到后面
+	ListMergeByKey = "merge"
+)
+
+type listMerge struct {
+	mode string
+	// ListMergeByKey时候元素的字段
+	field string
+}
+
+// 数组中的一个元素：元素内的相对key（""为元素本身，".name"、"[0]"为元素的字段以及下标） -> 值
+type listElement map[string]any
+
+// 按照优先级合并所有配置源，返回扁平化的配置以及每个key生效的配置源；
+// 数组的合并方式本身也是配置，先按照默认的方式合并读取合并方式，有配置的话再按照配置的方式合并
+// 不同配置源中同一个配置的不同写法（比如：pool-size、poolSize）按照宽松匹配合并为规范的写法，返回规范写法的索引
+func mergeSources(sources []*PropertySource) (map[string]any, map[string]string, canonicalIndex) {
+	valueMap, originMap, index := doMergeSources(sources, nil)
+	if strategies := listMergeStrategies(valueMap); len(strategies) > 0 {
+		valueMap, originMap, index = doMergeSources(sources, strategies)
+	}
+	return valueMap, originMap, index
+}
+
+func doMergeSources(sources []*PropertySource, strategies map[string]listMerge) (map[string]any, map[string]string, canonicalIndex) {
+	valueMap := map[string]any{}
+	originMap := map[string]string{}
+	index := canonicalIndex{}
+	for _, source := range sources {
+		keys := make([]string, 0, len(source.ValueMap))
+		for key := range source.ValueMap {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			index.add(key)
+		}
+
+		// 环境变量以及命令行中一个变量只能对应数组的一个下标（比如：GOLE_KAFKA_ADDRS_0），按照下标覆盖
+		if source.Type == SourceTypeEnvironment || source.Type == SourceTypeCommandLine {
+			for key, value := range source.ValueMap {
+				key = index.canonical(key)
+				valueMap[key] = value
+				originMap[key] = source.Name
+			}
+			continue
+		}
+		for key := range mergeValues(valueMap, source.ValueMap, strategies, index) {
+			originMap[key] = source.Name
+		}
+	}
+	// 被替换掉的数组下标
+	for key := range originMap {
+		if _, exist := valueMap[key]; !exist {
+			delete(originMap, key)
+		}
+	}
+	return valueMap, originMap, index
+}
+
+// 将扁平化的配置values合并到target中，values中的key按照index转换为规范的写法，数组按照strategies中的方式合并；返回写入target的key以及值
+func mergeValues(target, values map[string]any, strategies map[string]listMerge, index canonicalIndex) map[string]any {
+	written := map[string]any{}
+	lists := map[string]map[int]listElement{}
+	for key, value := range values {
+		key = index.canonical(key)
+		path, index, rest, ok := splitListKey(key)
+		if !ok {
+			// 非数组的值替换之前的数组
+			dropListKeys(target, key)
+			target[key] = value
+			written[key] = value
+			continue
+		}
+		if _, exist := lists[path]; !exist {
+			lists[path] = map[int]listElement{}
+		}
+		if _, exist := lists[path][index]; !exist {
+			lists[path][index] = listElement{}
+		}
+		lists[path][index][rest] = value
+	}
+
+	for path, elements := range lists {
+		indexes := make([]int, 0, len(elements))
+		for index := range elements {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+
+		strategy := strategies[relaxedKey(path)]
+		switch strategy.mode {
+		case ListMergeAppend:
+			offset := listSize(target, path)
+			for order, index := range indexes {
+				writeElement(target, written, path, offset+order, elements[index])
+			}
+		case ListMergeByKey:
+			existIndexes := listFieldIndexes(target, path, strategy.field)
+			size := listSize(target, path)
+			for _, index := range indexes {
+				element := elements[index]
+				fieldValue, exist := element["."+strategy.field]
+				if existIndex, matched := existIndexes[util.ToString(fieldValue)]; exist && matched {
+					mergeElement(target, written, path, existIndex, element)
+					continue
+				}
+				writeElement(target, written, path, size, element)
+				if exist {
+					existIndexes[util.ToString(fieldValue)] = size
+				}
+				size++
+			}
+		default:
+			dropListKeys(target, path)
+			delete(target, path)
+			for _, index := range indexes {
+				writeElement(target, written, path, index, elements[index])
+			}
+		}
+	}
+	return written
+}
+
+// 写入数组下标index的元素
+func writeElement(target, written map[string]any, path string, index int, element listElement) {
+	elementKey := path + "[" + strconv.Itoa(index) + "]"
+	for rest, value := range element {
+		target[elementKey+rest] = value
+		written[elementKey+rest] = value
+	}
+}
+
+// 合并数组下标index的元素：元素中的字段覆盖之前的字段，元素中的数组整体替换
+func mergeElement(target, written map[string]any, path string, index int, element listElement) {
+	elementKey := path + "[" + strconv.Itoa(index) + "]"
+	for rest := range element {
+		if listPath, _, _, ok := splitListKey(rest); ok {
+			dropListKeys(target, elementKey+listPath)
+		}
+	}
+	writeElement(target, written, path, index, element)
+}
+
+// 删除path对应数组的所有下标
+func dropListKeys(target map[string]any, path string) {
+	for key := range target {
+		if strings.HasPrefix(key, path+"[") {
+			delete(target, key)
+		}
+	}
+}
+
+// 数组的长度：最大的下标+1
+func listSize(target map[string]any, path string) int {
+	size := 0
+	for key := range target {
+		if listPath, index, _, ok := splitListKey(key); ok && listPath == path && index+1 > size {
+			size = index + 1
+		}
+	}
+	return size
+}
+
+// 数组中元素的字段值 -> 下标
+func listFieldIndexes(target map[string]any, path string, field string) map[string]int {
+	indexes := map[string]int{}
+	for key, value := range target {
+		if listPath, index, rest, ok := splitListKey(key); ok && listPath == path && rest == "."+field {
+			indexes[util.ToString(value)] = index
+		}
+	}
+	return indexes
+}
+
+// 拆分数组的key：a.b[1].c -> a.b、1、.c；只拆分第一层数组，非数组的key返回false
+func splitListKey(key string) (string, int, string, bool) {
+	start := strings.Index(key, "[")
+	if start <= 0 {
+		return "", 0, "", false
+	}
+	end := strings.Index(key[start:], "]")
+	if end < 0 {
+		return "", 0, "", false
+	}
+	index, err := strconv.Atoi(key[start+1 : start+end])
+	if err != nil {
+		return "", 0, "", false
+	}
+	return key[:start], index, key[start+end+1:], true
+}
+
+// 读取配置的数组合并方式：宽松的数组key -> 合并方式；不合法的合并方式按照replace处理
+func listMergeStrategies(valueMap map[string]any) map[string]listMerge {
+	strategies := map[string]listMerge{}
+	prefix := relaxedKey(listMergeKeyPrefix)
+	for key, value := range valueMap {
+		relaxed := relaxedKey(key)
+		if !strings.HasPrefix(relaxed, prefix) {
+			continue
+		}
+		mode, field, _ := strings.Cut(strings.TrimSpace(util.ToString(value)), ":")
+		mode, field = strings.ToLower(strings.TrimSpace(mode)), strings.TrimSpace(field)
+		switch {
+		case mode == ListMergeAppend:
+			strategies[strings.TrimPrefix(relaxed, prefix)] = listMerge{mode: mode}
+		case mode == ListMergeByKey && field != "":
+			strategies[strings.TrimPrefix(relaxed, prefix)] = listMerge{mode: mode, field: field}
+		}
+	}
+	return strategies
+}
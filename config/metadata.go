@@ -0,0 +1,276 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/simonalong/gole/util"
+)
+
+// KeyMetadata 配置key的元数据，由各个包注册，用于检查未知以及废弃的配置，并通过/config/metadata查看
+type KeyMetadata struct {
+	// 配置的key，"*"匹配任意一段，比如：gole.logger.group.*.level；数组的配置不需要带下标
+	Key string `json:"key"`
+	// 配置的类型：string、int、bool、duration、list、map等；map类型的配置下面的所有key都认为是合法的
+	Type string `json:"type"`
+	// 默认值
+	Default string `json:"default,omitempty"`
+	// 描述
+	Description string `json:"description,omitempty"`
+	// 是否已废弃
+	Deprecated bool `json:"deprecated,omitempty"`
+	// 废弃后替代的key
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// 注册的元数据：key -> 元数据
+var keyMetadataMap = map[string]KeyMetadata{}
+
+var keyMetadataLock sync.Mutex
+
+// did you mean 提示时候允许的最大编辑距离
+const maxSuggestDistance = 3
+
+// 模块名拼写错误提示时候允许的最大编辑距离
+const maxModuleSuggestDistance = 2
+
+// gole提供的模块（包括没有引入的包），没有注册元数据的模块与其相近的时候提示拼写错误，比如：gole.sever.port
+var goleModules = []string{
+	"gole.api", "gole.application", "gole.config", "gole.datasource", "gole.debug", "gole.emqx", "gole.endpoint", "gole.etcd", "gole.http",
+	"gole.kafka", "gole.logger", "gole.orm", "gole.profiles", "gole.redis", "gole.server", "gole.swagger", "gole.tracing",
+}
+
+func init() {
+	RegisterKeyMetadata(
+		KeyMetadata{Key: "gole.config.additional-location", Type: "string", Default: "./config/application-default.yml", Description: "额外的配置文件，优先级高于application-{profile}"},
+		KeyMetadata{Key: "gole.config.import", Type: "list", Description: "导入其他配置文件，支持optional:和dir:前缀"},
+		KeyMetadata{Key: "gole.config.watch.enable", Type: "bool", Default: "false", Description: "是否开启配置文件热加载"},
+		KeyMetadata{Key: "gole.config.watch.interval", Type: "duration", Default: "5s", Description: "配置文件热加载的检查间隔"},
+		KeyMetadata{Key: "gole.config.encrypt.key", Type: "string", Description: "加密配置的主密钥，只能通过命令行或者环境变量配置"},
+		KeyMetadata{Key: "gole.config.encrypt.file", Type: "string", Description: "加密配置的主密钥文件，只能通过命令行或者环境变量配置"},
+		KeyMetadata{Key: "gole.config.merge.lists", Type: "map", Description: "数组的合并方式：replace（默认）、append、merge:字段名，比如：gole.config.merge.lists.gole.kafka.addrs=append"},
+		KeyMetadata{Key: "gole.config.tree.locations", Type: "list", Description: "配置树的目录（比如：Kubernetes挂载的ConfigMap、Secret），每个文件为一个配置"},
+		KeyMetadata{Key: "gole.config.tree.interval", Type: "duration", Default: "5s", Description: "配置树目录变更的检查间隔"},
+		KeyMetadata{Key: "gole.config.history.max-size", Type: "int", Default: "100", Description: "保留的运行时配置变更记录数"},
+		KeyMetadata{Key: "gole.config.history.file", Type: "string", Description: "运行时配置变更记录持久化的文件，不配置则不持久化"},
+		KeyMetadata{Key: "gole.config.activate.on-profile", Type: "string", Description: "多文档yaml中文档生效的profile"},
+		KeyMetadata{Key: "gole.profiles.active", Type: "string", Description: "激活的profile，多个用逗号分隔"},
+		KeyMetadata{Key: "gole.profiles.group", Type: "map", Description: "profile分组，比如：gole.profiles.group.prod=prod-db,prod-mq"},
+	)
+}
+
+// RegisterKeyMetadata 注册配置key的元数据；注册后会检查已加载配置中同一模块（比如：gole.server）下未知以及废弃的配置，并打印提示
+func RegisterKeyMetadata(metadataList ...KeyMetadata) {
+	keyMetadataLock.Lock()
+	for _, metadata := range metadataList {
+		keyMetadataMap[metadata.Key] = metadata
+	}
+	keyMetadataLock.Unlock()
+	defaultConfig.warnKeys()
+}
+
+// GetKeyMetadata 获取所有注册的配置key的元数据，按照key排序
+func GetKeyMetadata() []KeyMetadata {
+	keyMetadataLock.Lock()
+	defer keyMetadataLock.Unlock()
+	var metadataList []KeyMetadata
+	for _, metadata := range keyMetadataMap {
+		metadataList = append(metadataList, metadata)
+	}
+	sort.Slice(metadataList, func(i, j int) bool { return metadataList[i].Key < metadataList[j].Key })
+	return metadataList
+}
+
+// CheckKeys 检查当前配置中未知以及废弃的配置，返回提示信息；
+// 只检查gole下已经注册过元数据的模块（gole.xxx），没有引入的模块不检查；没有注册的模块名与gole的模块相近的（比如：gole.sever）提示拼写错误
+func CheckKeys() []string {
+	return defaultConfig.CheckKeys()
+}
+
+// CheckKeys 检查当前实例配置中未知以及废弃的配置，返回提示信息，规则同包级别的CheckKeys
+func (c *Config) CheckKeys() []string {
+	property := c.currentProperty()
+	if nil == property {
+		return nil
+	}
+
+	keyMetadataLock.Lock()
+	defer keyMetadataLock.Unlock()
+	modules := map[string]bool{}
+	for key := range keyMetadataMap {
+		modules[keyModule(key)] = true
+	}
+
+	var warnings []string
+	for _, key := range sortedKeys(property.ValueMap) {
+		if !strings.HasPrefix(key, "gole.") {
+			continue
+		}
+		if !modules[keyModule(key)] {
+			if suggestion := suggestModuleKey(key, modules); suggestion != "" {
+				warnings = append(warnings, fmt.Sprintf("配置[%s]不是已知的配置，是否为：%s", key, suggestion))
+			}
+			continue
+		}
+		metadata, exist := matchKeyMetadata(key)
+		if !exist {
+			warning := fmt.Sprintf("配置[%s]不是已知的配置", key)
+			if suggestion := suggestKey(key); suggestion != "" {
+				warning += fmt.Sprintf("，是否为：%s", suggestion)
+			}
+			warnings = append(warnings, warning)
+		} else if metadata.Deprecated {
+			warning := fmt.Sprintf("配置[%s]已废弃", key)
+			if metadata.Replacement != "" {
+				warning += fmt.Sprintf("，请使用：%s", metadata.Replacement)
+			}
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// GetConfigMetadata 查看所有支持的配置
+func GetConfigMetadata(c *gin.Context) {
+	if !checkEndpointAuth(c) {
+		return
+	}
+	c.Data(200, "application/json; charset=utf-8", []byte(util.ObjectToJson(GetKeyMetadata())))
+}
+
+// 打印未知以及废弃配置的提示，同一个提示只打印一次
+func (c *Config) warnKeys() {
+	for _, warning := range c.CheckKeys() {
+		c.warnLock.Lock()
+		warned := c.warnedKeys[warning]
+		c.warnedKeys[warning] = true
+		c.warnLock.Unlock()
+		if !warned {
+			log.Printf("%s", warning)
+		}
+	}
+}
+
+// 查找配置对应的元数据：去掉数组下标后按照宽松的方式逐段匹配，map类型的元数据匹配其下面所有的key
+func matchKeyMetadata(key string) (KeyMetadata, bool) {
+	keyParts := strings.Split(relaxedKey(keyIndexPattern.ReplaceAllString(key, "")), ".")
+	for pattern, metadata := range keyMetadataMap {
+		patternParts := strings.Split(relaxedKey(pattern), ".")
+		if len(keyParts) < len(patternParts) || (len(keyParts) > len(patternParts) && metadata.Type != "map") {
+			continue
+		}
+		matched := true
+		for index, part := range patternParts {
+			if part != "*" && part != keyParts[index] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return metadata, true
+		}
+	}
+	return KeyMetadata{}, false
+}
+
+// 编辑距离最小的已知配置
+func suggestKey(key string) string {
+	key = strings.ToLower(keyIndexPattern.ReplaceAllString(key, ""))
+	suggestion, minDistance := "", maxSuggestDistance+1
+	for candidate, metadata := range keyMetadataMap {
+		if metadata.Deprecated {
+			continue
+		}
+		distance := editDistance(key, strings.ToLower(candidate))
+		if distance < minDistance || (distance == minDistance && candidate < suggestion) {
+			suggestion, minDistance = candidate, distance
+		}
+	}
+	return suggestion
+}
+
+// 模块名拼写错误的配置的提示：模块名替换为相近的模块（注册过元数据的或者gole提供的）后，有相近的已知配置则使用该配置，否则为替换模块名后的key；
+// 模块名没有相近的返回空
+func suggestModuleKey(key string, modules map[string]bool) string {
+	module := keyModule(key)
+	candidates := append([]string{}, goleModules...)
+	for registered := range modules {
+		candidates = append(candidates, registered)
+	}
+	suggestion, minDistance := "", maxModuleSuggestDistance+1
+	for _, candidate := range candidates {
+		distance := editDistance(module, candidate)
+		if distance == 0 {
+			// gole提供但是没有引入的模块
+			return ""
+		}
+		if distance*2 > len(strings.TrimPrefix(candidate, "gole.")) {
+			continue
+		}
+		if distance < minDistance || (distance == minDistance && candidate < suggestion) {
+			suggestion, minDistance = candidate, distance
+		}
+	}
+	if suggestion == "" {
+		return ""
+	}
+
+	parts := strings.SplitN(key, ".", 3)
+	suggestionKey := suggestion
+	if len(parts) == 3 {
+		suggestionKey += "." + parts[2]
+	}
+	if similar := suggestKey(suggestionKey); similar != "" {
+		return similar
+	}
+	return suggestionKey
+}
+
+// 配置所属的模块，比如：gole.server.port -> gole.server
+func keyModule(key string) string {
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) < 2 {
+		return key
+	}
+	return relaxedKey(keyIndexPattern.ReplaceAllString(parts[0]+"."+parts[1], ""))
+}
+
+func editDistance(source, target string) int {
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func sortedKeys(valueMap map[string]any) []string {
+	keys := make([]string, 0, len(valueMap))
+	for key := range valueMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const placeholderPrefix = "${"
+const placeholderSuffix = "}"
+const placeholderDefaultSeparator = ":"
+
+// PlaceholderError 占位符解析异常：key不存在且没有默认值，或者出现了循环引用
+type PlaceholderError struct {
+	// 无法解析的key
+	Key string
+	// 解析链路，比如：a -> b -> a
+	Chain []string
+	// 是否为循环引用
+	Cycle bool
+}
+
+func (e *PlaceholderError) Error() string {
+	if e.Cycle {
+		return fmt.Sprintf("配置占位符存在循环引用：%s", strings.Join(appendChain(e.Chain, e.Key), " -> "))
+	}
+	if len(e.Chain) > 0 {
+		return fmt.Sprintf("配置占位符无法解析：${%s}，引用链路：%s", e.Key, strings.Join(e.Chain, " -> "))
+	}
+	return fmt.Sprintf("配置占位符无法解析：${%s}", e.Key)
+}
+
+// ResolvePlaceholders 解析文本中的占位符，支持格式：
+//   - ${key}：读取配置key的值
+//   - ${key:default}：key不存在时候使用默认值，默认值中也可以有占位符
+//   - http://${host}:${port}/api：文本中嵌入多个占位符
+//
+// key的查找顺序：配置 > 环境变量 > 默认值；引用的值为加密配置（ENC(...)）时候会解密
+func ResolvePlaceholders(text string) (string, error) {
+	return defaultConfig.ResolvePlaceholders(text)
+}
+
+// ResolvePlaceholders 解析文本中的占位符，规则同包级别的ResolvePlaceholders
+func (c *Config) ResolvePlaceholders(text string) (string, error) {
+	return resolveText(c.currentProperty(), text, nil)
+}
+
+// 解析配置key对应值中的占位符，解析失败的时候打印异常并返回原值
+func resolveValueOrRaw(property *ApplicationProperty, key string, value any) any {
+	result, err := resolveValue(property, key, value)
+	if err != nil {
+		log.Printf("读取配置[%s]失败，%v", key, err)
+		return value
+	}
+	return result
+}
+
+// 对字符串、map、数组中所有的字符串占位符进行解析，其他类型原样返回
+func resolveValue(property *ApplicationProperty, key string, value any) (any, error) {
+	if key == "" {
+		return doResolveValue(property, value, nil)
+	}
+	return doResolveValue(property, value, []string{key})
+}
+
+func doResolveValue(property *ApplicationProperty, value any, chain []string) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch v := value.(type) {
+	case string:
+		if isEncryptedValue(v) {
+			return decryptValue(v)
+		}
+		if !strings.Contains(v, placeholderPrefix) {
+			return v, nil
+		}
+		// 整个值只有一个占位符，则允许引用非叶子节点，比如：${place}
+		if key, ok := wholePlaceholder(v); ok && nil != property {
+			if _, exist := property.ValueMap[key]; !exist {
+				if deepValue := doGetValue(property.ValueDeepMap, key); deepValue != nil && !isLeafValue(deepValue) {
+					if containsKey(chain, key) {
+						return nil, &PlaceholderError{Key: key, Chain: chain, Cycle: true}
+					}
+					return doResolveValue(property, deepValue, appendChain(chain, key))
+				}
+			}
+		}
+		return resolveText(property, v, chain)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		result := make(map[string]any, rv.Len())
+		for mapR := rv.MapRange(); mapR.Next(); {
+			item, err := doResolveValue(property, mapR.Value().Interface(), chain)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprintf("%v", mapR.Key().Interface())] = item
+		}
+		return result, nil
+	case reflect.Slice, reflect.Array:
+		result := make([]any, 0, rv.Len())
+		for index := 0; index < rv.Len(); index++ {
+			item, err := doResolveValue(property, rv.Index(index).Interface(), chain)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, item)
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+func resolveText(property *ApplicationProperty, text string, chain []string) (string, error) {
+	if !strings.Contains(text, placeholderPrefix) {
+		return text, nil
+	}
+
+	var builder strings.Builder
+	for {
+		start := strings.Index(text, placeholderPrefix)
+		if start < 0 {
+			builder.WriteString(text)
+			break
+		}
+		end := findPlaceholderEnd(text, start)
+		if end < 0 {
+			// 没有闭合的占位符按照普通文本处理
+			builder.WriteString(text)
+			break
+		}
+
+		builder.WriteString(text[:start])
+		resolved, err := resolvePlaceholder(property, text[start+len(placeholderPrefix):end], chain)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(resolved)
+		text = text[end+len(placeholderSuffix):]
+	}
+	return builder.String(), nil
+}
+
+// 解析占位符内部的内容，比如：key:default
+func resolvePlaceholder(property *ApplicationProperty, content string, chain []string) (string, error) {
+	key, defaultValue, hasDefault := splitPlaceholder(content)
+
+	// key本身也可以是占位符，比如：${${env.name}.url}
+	key, err := resolveText(property, key, chain)
+	if err != nil {
+		return "", err
+	}
+	key = strings.TrimSpace(key)
+
+	if containsKey(chain, key) {
+		return "", &PlaceholderError{Key: key, Chain: chain, Cycle: true}
+	}
+
+	if value, exist := lookupPlaceholderKey(property, key); exist {
+		if isEncryptedValue(value) {
+			return decryptValue(value)
+		}
+		return resolveText(property, value, appendChain(chain, key))
+	}
+
+	if hasDefault {
+		return resolveText(property, defaultValue, chain)
+	}
+	return "", &PlaceholderError{Key: key, Chain: chain}
+}
+
+// 占位符的key查找：先配置，后环境变量；用于查看的配置中，环境变量的值按照占位符的key脱敏
+func lookupPlaceholderKey(property *ApplicationProperty, key string) (string, bool) {
+	if nil != property {
+		if value, exist := property.ValueMap[property.canonicalKey(key)]; exist && value != nil {
+			return fmt.Sprintf("%v", value), true
+		}
+	}
+
+	value, exist := os.LookupEnv(key)
+	if !exist {
+		// 环境变量的宽松匹配：gole.server.port -> GOLE_SERVER_PORT
+		value, exist = os.LookupEnv(keyToEnvName(key))
+	}
+	if exist && nil != property && property.display {
+		return fmt.Sprintf("%v", maskValue(property, key, value)), true
+	}
+	return value, exist
+}
+
+// 找到与start处的"${"匹配的"}"，支持嵌套
+func findPlaceholderEnd(text string, start int) int {
+	depth := 0
+	for index := start; index < len(text); index++ {
+		if strings.HasPrefix(text[index:], placeholderPrefix) {
+			depth++
+			index += len(placeholderPrefix) - 1
+			continue
+		}
+		if strings.HasPrefix(text[index:], placeholderSuffix) {
+			depth--
+			if depth == 0 {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+// 拆分出key和默认值，嵌套占位符中的":"不作为分隔符
+func splitPlaceholder(content string) (string, string, bool) {
+	depth := 0
+	for index := 0; index < len(content); index++ {
+		if strings.HasPrefix(content[index:], placeholderPrefix) {
+			depth++
+			index += len(placeholderPrefix) - 1
+			continue
+		}
+		if strings.HasPrefix(content[index:], placeholderSuffix) {
+			depth--
+			continue
+		}
+		if depth == 0 && strings.HasPrefix(content[index:], placeholderDefaultSeparator) {
+			return content[:index], content[index+len(placeholderDefaultSeparator):], true
+		}
+	}
+	return content, "", false
+}
+
+// 判断整个值是否只是一个占位符，比如：${place}
+func wholePlaceholder(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, placeholderPrefix) || findPlaceholderEnd(value, 0) != len(value)-len(placeholderSuffix) {
+		return "", false
+	}
+	key, _, _ := splitPlaceholder(value[len(placeholderPrefix) : len(value)-len(placeholderSuffix)])
+	return strings.TrimSpace(key), true
+}
+
+func isLeafValue(value any) bool {
+	kind := reflect.ValueOf(value).Kind()
+	return kind != reflect.Map && kind != reflect.Slice && kind != reflect.Array
+}
+
+func containsKey(chain []string, key string) bool {
+	for _, item := range chain {
+		if item == key {
+			return true
+		}
+	}
+	return false
+}
+
+// 拷贝后追加，避免多个分支共享同一个底层数组
+func appendChain(chain []string, key string) []string {
+	result := make([]string, 0, len(chain)+1)
+	result = append(result, chain...)
+	return append(result, key)
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/simonalong/gole/listener"
+	"github.com/simonalong/gole/util"
+)
+
+const profilesActiveKey = "gole.profiles.active"
+
+// 分组的前缀，比如：gole.profiles.group.prod=prod-db,prod-mq，激活prod时候会同时激活prod-db和prod-mq
+const profilesGroupPrefix = "gole.profiles.group."
+
+// 多文档yaml中文档的生效条件，比如：gole.config.activate.on-profile=prod
+const onProfileKey = "gole.config.activate.on-profile"
+
+// GetActiveProfiles 获取当前激活的profile，按照优先级从低到高排列（包括分组展开后的profile）
+func GetActiveProfiles() []string {
+	return defaultConfig.GetActiveProfiles()
+}
+
+// GetActiveProfiles 获取当前激活的profile，按照优先级从低到高排列（包括分组展开后的profile）
+func (c *Config) GetActiveProfiles() []string {
+	if profiles, ok := c.activeProfiles.Load().([]string); ok {
+		return append([]string{}, profiles...)
+	}
+	return []string{}
+}
+
+func (c *Config) setActiveProfiles(profiles []string) {
+	c.activeProfiles.Store(append([]string{}, profiles...))
+}
+
+// 激活的profile写入运行时配置，便于通过gole.profiles.active读取；每次加载配置都会写入，不作为变更记录
+func (c *Config) setActiveProfileValue(activeProfile string) {
+	c.updateRuntimeSource(func(_ *ApplicationProperty, valueMap map[string]any) bool {
+		valueMap[profilesActiveKey] = activeProfile
+		return true
+	})
+	c.publishEvent(listener.ConfigChangeEvent{Key: profilesActiveKey, Value: activeProfile})
+}
+
+// 读取激活的profile，支持逗号分隔的多个profile，后面的优先级高；优先级：命令行 > 环境变量 > 本地配置
+func (c *Config) getActiveProfiles() []string {
+	active, exist := lookupExternalValue(profilesActiveKey)
+	if !exist || strings.TrimSpace(active) == "" {
+		active = c.GetValueString(profilesActiveKey)
+	}
+
+	var profiles []string
+	for _, profile := range splitProfiles(active) {
+		profiles = c.expandProfileGroup(profile, profiles, nil)
+	}
+	return profiles
+}
+
+// 展开profile分组：先profile本身，后分组中的成员，成员也可以是分组；重复的profile只保留第一个
+func (c *Config) expandProfileGroup(profile string, profiles []string, chain []string) []string {
+	if containsKey(profiles, profile) || containsKey(chain, profile) {
+		return profiles
+	}
+	profiles = append(profiles, profile)
+
+	for _, member := range c.profileGroupMembers(profile) {
+		profiles = c.expandProfileGroup(member, profiles, appendChain(chain, profile))
+	}
+	return profiles
+}
+
+// 分组的成员，支持逗号分隔的字符串以及数组
+func (c *Config) profileGroupMembers(profile string) []string {
+	value := c.GetValue(profilesGroupPrefix + profile)
+	if nil == value {
+		return nil
+	}
+	if reflect.ValueOf(value).Kind() == reflect.Slice {
+		var members []string
+		for _, item := range value.([]any) {
+			members = append(members, splitProfiles(util.ToString(item))...)
+		}
+		return members
+	}
+	return splitProfiles(util.ToString(value))
+}
+
+func splitProfiles(value string) []string {
+	var profiles []string
+	for _, profile := range strings.Split(value, ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// 判断文档的生效条件是否满足：多个profile用逗号分隔，满足任意一个即可；!prod表示没有激活prod
+func (c *Config) matchOnProfile(condition string) bool {
+	profiles := c.GetActiveProfiles()
+	for _, item := range splitProfiles(condition) {
+		if strings.HasPrefix(item, "!") {
+			if !containsKey(profiles, strings.TrimSpace(item[1:])) {
+				return true
+			}
+			continue
+		}
+		if containsKey(profiles, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// 解析yaml的配置，支持"---"分隔的多个文档，后面文档的优先级高；
+// 文档中配置了gole.config.activate.on-profile的，只有在对应profile激活时候才生效
+func (c *Config) yamlContentToMap(content string) (map[string]any, error) {
+	documents, err := util.YamlToMaps(content)
+	if err != nil {
+		return nil, err
+	}
+
+	valueMap := map[string]any{}
+	for _, document := range documents {
+		// 保留yaml解析出来的类型，比如："0123"、"yes"仍然为字符串
+		documentMap := util.DeepMapToPropertiesMap(document)
+		if condition, exist := documentMap[onProfileKey]; exist {
+			delete(documentMap, onProfileKey)
+			if !c.matchOnProfile(util.ToString(condition)) {
+				continue
+			}
+		}
+		for key, value := range documentMap {
+			valueMap[key] = value
+		}
+	}
+	return valueMap, nil
+}
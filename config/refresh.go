@@ -0,0 +1,106 @@
+package config
+
+import (
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/simonalong/gole/listener"
+)
+
+// Watcher 自动刷新的配置对象：前缀下的配置有变更时候会重新绑定，并通知注册的回调
+type Watcher[T any] struct {
+	config *Config
+	prefix string
+	// 当前绑定的值（*T），每次刷新整体替换
+	value        atomic.Value
+	refreshLock  sync.Mutex
+	callbackLock sync.Mutex
+	callbacks    []func(oldValue, newValue T)
+	// 移除配置变更的监听
+	removeListener func()
+	closeOnce      sync.Once
+}
+
+// Watch 将前缀prefix下的配置绑定到结构体T上（规则同Bind），并在前缀下的配置变更（配置变更事件）时候自动重新绑定
+//
+//	limitCfg, err := config.Watch[LimitConfig]("gole.limit")
+//	limitCfg.OnChange(func(oldValue, newValue LimitConfig) {})
+//	limitCfg.Get().Qps
+//	defer limitCfg.Close()
+func Watch[T any](prefix string) (*Watcher[T], error) {
+	return WatchFrom[T](defaultConfig, prefix)
+}
+
+// WatchFrom 将配置实例c中前缀prefix下的配置绑定到结构体T上，并在c的配置变更时候自动重新绑定，规则同Watch
+func WatchFrom[T any](c *Config, prefix string) (*Watcher[T], error) {
+	value, err := BindFrom[T](c, prefix)
+	if err != nil {
+		return nil, err
+	}
+	watcher := &Watcher[T]{config: c, prefix: prefix}
+	watcher.value.Store(&value)
+	watcher.removeListener = c.AddChangeListener(watcher.onConfigChange)
+	return watcher, nil
+}
+
+// Close 停止自动刷新：移除配置变更的监听，之后Get返回最后一次绑定的值
+func (w *Watcher[T]) Close() {
+	w.closeOnce.Do(w.removeListener)
+}
+
+// Get 获取当前的值，返回的是一个完整的快照
+func (w *Watcher[T]) Get() T {
+	return *w.value.Load().(*T)
+}
+
+// OnChange 注册值变更的回调，回调中有变更前后的值
+func (w *Watcher[T]) OnChange(callback func(oldValue, newValue T)) {
+	w.callbackLock.Lock()
+	defer w.callbackLock.Unlock()
+	w.callbacks = append(w.callbacks, callback)
+}
+
+// Refresh 重新绑定配置，值有变化则通知回调；绑定失败的时候保留之前的值
+func (w *Watcher[T]) Refresh() error {
+	w.refreshLock.Lock()
+	defer w.refreshLock.Unlock()
+
+	newValue, err := BindFrom[T](w.config, w.prefix)
+	if err != nil {
+		log.Printf("刷新配置[%s]失败，保留之前的值，%v", w.prefix, err)
+		return err
+	}
+	oldValue := w.Get()
+	if reflect.DeepEqual(oldValue, newValue) {
+		return nil
+	}
+	w.value.Store(&newValue)
+
+	w.callbackLock.Lock()
+	callbacks := append([]func(oldValue, newValue T){}, w.callbacks...)
+	w.callbackLock.Unlock()
+	for _, callback := range callbacks {
+		callback(oldValue, newValue)
+	}
+	return nil
+}
+
+func (w *Watcher[T]) onConfigChange(event listener.ConfigChangeEvent) {
+	if !matchPrefix(w.prefix, event.Key) {
+		return
+	}
+	_ = w.Refresh()
+}
+
+// 判断key是否在前缀下（宽松匹配），比如：gole.limit 匹配 gole.limit、gole.limit.qps、gole.limit.ips[0]；
+// 修改父级key（比如：gole）也会影响前缀下的配置
+func matchPrefix(prefix, key string) bool {
+	if prefix == "" {
+		return true
+	}
+	prefix, key = relaxedKey(prefix), relaxedKey(key)
+	return key == prefix || strings.HasPrefix(key, prefix+".") || strings.HasPrefix(prefix, key+".")
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/simonalong/gole/util"
+)
+
+// 配置源类型，优先级从低到高：文件 < 远程配置中心、配置树 < 环境变量 < 命令行 < 运行时修改
+const (
+	// SourceTypeFile 默认配置文件以及代码中通过LoadFile、AppendFile加载的配置文件
+	SourceTypeFile = "file"
+	// SourceTypeProfile 对应profile的配置文件，比如：application-local.yml
+	SourceTypeProfile = "profile"
+	// SourceTypeAdditional gole.config.additional-location 对应的配置文件
+	SourceTypeAdditional = "additional"
+	// SourceTypeRemote 远程配置中心，比如：etcd
+	SourceTypeRemote = "remote"
+	// SourceTypeConfigTree 配置树，比如：Kubernetes中挂载的ConfigMap、Secret目录
+	SourceTypeConfigTree = "configTree"
+	// SourceTypeEnvironment 环境变量
+	SourceTypeEnvironment = "environment"
+	// SourceTypeCommandLine 命令行参数
+	SourceTypeCommandLine = "commandLine"
+	// SourceTypeRuntime 运行时通过SetValue或者/config/update修改的配置
+	SourceTypeRuntime = "runtime"
+)
+
+var sourceTypeRank = map[string]int{
+	SourceTypeFile:        0,
+	SourceTypeProfile:     0,
+	SourceTypeAdditional:  0,
+	SourceTypeRemote:      1,
+	SourceTypeConfigTree:  1,
+	SourceTypeEnvironment: 2,
+	SourceTypeCommandLine: 3,
+	SourceTypeRuntime:     4,
+}
+
+// PropertySource 配置源
+type PropertySource struct {
+	// 配置源名字，文件类型的为文件路径
+	Name string
+	// 配置源类型：file、profile、additional、remote、configTree、environment、commandLine、runtime
+	Type string
+	// 扁平化的配置，比如：a.b.c、a.b[0]
+	ValueMap map[string]any
+	// 文件类型配置源的解析器，用于文件变更时候重新加载
+	parser func(string) (map[string]any, error)
+}
+
+// PropertySourceInfo 配置源的概要信息，Priority越大优先级越高
+type PropertySourceInfo struct {
+	Name     string
+	Type     string
+	Priority int
+}
+
+// PropertyOrigin 配置值及其来源
+type PropertyOrigin struct {
+	Key   string
+	Value any
+	// 生效的配置源
+	Source string
+	// 被覆盖的值，按照优先级从高到低排列
+	Overridden []OverriddenValue
+}
+
+// OverriddenValue 被覆盖的配置值
+type OverriddenValue struct {
+	Source string
+	Value  any
+}
+
+// 获取当前配置的快照，没有加载过任何配置时候返回nil
+func (c *Config) currentProperty() *ApplicationProperty {
+	if property, ok := c.snapshot.Load().(*ApplicationProperty); ok {
+		return property
+	}
+	return nil
+}
+
+// 添加配置源：相同类型按照添加顺序，后添加的优先级高；不同类型按照类型的优先级排列
+func (c *Config) addPropertySource(newSources ...*PropertySource) {
+	c.updatePropertySources(func(sources []*PropertySource) []*PropertySource {
+		return append(sources, newSources...)
+	})
+}
+
+// 清理之前所有的配置源，只保留新的配置源
+func (c *Config) replacePropertySources(sources ...*PropertySource) {
+	c.updatePropertySources(func([]*PropertySource) []*PropertySource {
+		return sources
+	})
+}
+
+// SetPropertySource 设置配置源：已有同名的配置源则替换，否则按照类型的优先级添加；并对变更的key发布配置变更事件
+// 用于扩展外部的配置源，比如：etcd、k8s的ConfigMap等，valueMap为扁平化的配置，比如：a.b.c、a.b[0]
+func SetPropertySource(name string, sourceType string, valueMap map[string]any) {
+	defaultConfig.SetPropertySource(name, sourceType, valueMap)
+}
+
+// SetPropertySource 设置配置源：已有同名的配置源则替换，否则按照类型的优先级添加；并对变更的key发布配置变更事件
+func (c *Config) SetPropertySource(name string, sourceType string, valueMap map[string]any) {
+	copyMap := make(map[string]any, len(valueMap))
+	for key, value := range valueMap {
+		copyMap[key] = value
+	}
+	newSource := &PropertySource{Name: name, Type: sourceType, ValueMap: copyMap}
+
+	oldProperty, newProperty := c.updatePropertySources(func(sources []*PropertySource) []*PropertySource {
+		for index, source := range sources {
+			if source.Name == name {
+				sources[index] = newSource
+				return sources
+			}
+		}
+		return append(sources, newSource)
+	})
+	c.publishChangeEvents(oldProperty, newProperty)
+}
+
+// RemovePropertySource 删除对应名字的配置源，并对变更的key发布配置变更事件
+func RemovePropertySource(name string) {
+	defaultConfig.RemovePropertySource(name)
+}
+
+// RemovePropertySource 删除对应名字的配置源，并对变更的key发布配置变更事件
+func (c *Config) RemovePropertySource(name string) {
+	oldProperty, newProperty := c.updatePropertySources(func(sources []*PropertySource) []*PropertySource {
+		var result []*PropertySource
+		for _, source := range sources {
+			if source.Name != name {
+				result = append(result, source)
+			}
+		}
+		return result
+	})
+	c.publishChangeEvents(oldProperty, newProperty)
+}
+
+// 修改运行时的配置源，valueMap为运行时配置的拷贝；update返回false则放弃修改；返回修改前后的快照以及是否修改
+func (c *Config) updateRuntimeSource(update func(property *ApplicationProperty, valueMap map[string]any) bool) (*ApplicationProperty, *ApplicationProperty, bool) {
+	changed := false
+	oldProperty, newProperty := c.updatePropertySources(func(sources []*PropertySource) []*PropertySource {
+		runtimeIndex := -1
+		valueMap := map[string]any{}
+		for index, source := range sources {
+			if source.Type == SourceTypeRuntime {
+				runtimeIndex = index
+				for k, v := range source.ValueMap {
+					valueMap[k] = v
+				}
+			}
+		}
+
+		if !update(buildProperty(sources), valueMap) {
+			return sources
+		}
+		changed = true
+		runtimeSource := &PropertySource{Name: SourceTypeRuntime, Type: SourceTypeRuntime, ValueMap: valueMap}
+		if runtimeIndex == -1 {
+			return append(sources, runtimeSource)
+		}
+		sources[runtimeIndex] = runtimeSource
+		return sources
+	})
+	return oldProperty, newProperty, changed
+}
+
+// 在写锁内基于当前配置源的拷贝生成新的配置源，并原子替换配置快照；返回替换前后的快照
+func (c *Config) updatePropertySources(update func(sources []*PropertySource) []*PropertySource) (*ApplicationProperty, *ApplicationProperty) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	oldProperty := c.currentProperty()
+	var sources []*PropertySource
+	if oldProperty != nil {
+		sources = append(sources, oldProperty.Sources...)
+	}
+	sources = update(sources)
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sourceTypeRank[sources[i].Type] < sourceTypeRank[sources[j].Type]
+	})
+	newProperty := buildProperty(sources)
+	c.snapshot.Store(newProperty)
+	return oldProperty, newProperty
+}
+
+// 按照优先级合并所有配置源（数组的合并方式见mergeSources），生成扁平化和深层的配置
+func buildProperty(sources []*PropertySource) *ApplicationProperty {
+	valueMap, originMap, index := mergeSources(sources)
+	return &ApplicationProperty{
+		ValueMap:       valueMap,
+		ValueDeepMap:   util.PropertiesMapToDeepMap(valueMap),
+		Sources:        sources,
+		originMap:      originMap,
+		canonicalIndex: index,
+	}
+}
+
+// GetPropertySources 获取所有的配置源，按照优先级从高到低排列
+func GetPropertySources() []PropertySourceInfo {
+	return defaultConfig.GetPropertySources()
+}
+
+// GetPropertySources 获取所有的配置源，按照优先级从高到低排列
+func (c *Config) GetPropertySources() []PropertySourceInfo {
+	property := c.currentProperty()
+	if nil == property {
+		return []PropertySourceInfo{}
+	}
+	var infos []PropertySourceInfo
+	for index := len(property.Sources) - 1; index >= 0; index-- {
+		source := property.Sources[index]
+		infos = append(infos, PropertySourceInfo{Name: source.Name, Type: source.Type, Priority: index})
+	}
+	return infos
+}
+
+// GetValueOrigin 获取配置值的来源以及被覆盖的值，key不存在则返回nil；加密配置的值不会解密
+func GetValueOrigin(key string) *PropertyOrigin {
+	return defaultConfig.GetValueOrigin(key)
+}
+
+// GetValueOrigin 获取配置值的来源以及被覆盖的值，key不存在则返回nil；加密配置的值不会解密
+func (c *Config) GetValueOrigin(key string) *PropertyOrigin {
+	return valueOrigin(displayProperty(c.currentProperty()), key)
+}
+
+func valueOrigin(property *ApplicationProperty, key string) *PropertyOrigin {
+	if nil == property {
+		return nil
+	}
+	key = property.canonicalKey(key)
+	value, exist := property.ValueMap[key]
+	if !exist {
+		return nil
+	}
+
+	origin := &PropertyOrigin{Key: key, Value: resolveValueOrRaw(property, key, value), Source: property.originMap[key], Overridden: []OverriddenValue{}}
+	overridden := false
+	for index := len(property.Sources) - 1; index >= 0; index-- {
+		source := property.Sources[index]
+		// 生效的配置源之后的都是被覆盖的值；数组追加时候，优先级高的配置源中相同下标的值并不是生效的值
+		if !overridden {
+			overridden = source.Name == origin.Source
+			continue
+		}
+		sourceValue, exist := lookupRelaxedValue(source.ValueMap, key)
+		if !exist {
+			continue
+		}
+		// 被覆盖的值与生效的值一样脱敏
+		origin.Overridden = append(origin.Overridden, OverriddenValue{Source: source.Name, Value: maskValue(property, key, sourceValue)})
+	}
+	return origin
+}
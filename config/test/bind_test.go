@@ -0,0 +1,120 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+	"github.com/simonalong/gole/validate"
+)
+
+type BindRetry struct {
+	Times    int           `yaml:"times"`
+	Interval time.Duration `default:"1s"`
+}
+
+type BindCfg struct {
+	Name           string            `yaml:"name"`
+	ConnectTimeout time.Duration     `yaml:"connect-timeout"`
+	ReadTimeout    time.Duration     // 属性名宽松匹配：read_timeout
+	WriteTimeout   time.Duration     `default:"3s"`
+	MaxSize        config.ByteSize   `json:"max-size"`
+	Tags           []string          `yaml:"tags"`
+	Servers        []string          `yaml:"servers"`
+	Labels         map[string]string `yaml:"labels"`
+	Retry          BindRetry         `yaml:"retry"`
+	Backoff        BindRetry         `yaml:"backoff"`
+	Level          string            `default:"info"`
+	Ignore         string            `yaml:"-"`
+}
+
+type BindErrCfg struct {
+	Port    int             `yaml:"port"`
+	Timeout time.Duration   `yaml:"timeout"`
+	Size    config.ByteSize `yaml:"size"`
+	Name    string          `yaml:"name" required:"true"`
+}
+
+type BindCheckCfg struct {
+	Network string         `match:"value={tcp, unix}" errMsg:"network值不合法，只可为两个值：tcp和unix"`
+	Port    int            `yaml:"port" match:"range=[1, 65535]" errMsg:"port需要在1到65535之间"`
+	Retry   BindCheckRetry `yaml:"retry"`
+}
+
+type BindCheckRetry struct {
+	Times int `yaml:"times" match:"range=[0, 10]" errMsg:"times不能超过10"`
+}
+
+// 测试：绑定配置到结构体
+func TestBind(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+
+	cfg, err := config.Bind[BindCfg]("gole.bind")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, cfg.Name, "bind-name")
+	assert.Equal(t, cfg.ConnectTimeout, 5*time.Second)
+	assert.Equal(t, cfg.ReadTimeout, 200*time.Millisecond)
+	assert.Equal(t, cfg.WriteTimeout, 3*time.Second)
+	assert.Equal(t, cfg.MaxSize, config.ByteSize(10*1024*1024))
+	assert.Equal(t, cfg.Tags, []string{"a", "b"})
+	assert.Equal(t, cfg.Servers, []string{"127.0.0.1:1883", "127.0.0.2:1883"})
+	assert.Equal(t, cfg.Labels, map[string]string{"zone": "cn"})
+	assert.Equal(t, cfg.Retry, BindRetry{Times: 3, Interval: time.Second})
+	assert.Equal(t, cfg.Backoff, BindRetry{Interval: time.Second})
+	assert.Equal(t, cfg.Level, "info")
+}
+
+// 测试：所有不合法以及缺失的key汇总到一个异常中
+func TestBindError(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+
+	_, err := config.Bind[BindErrCfg]("gole.bind-err")
+	var bindErr *config.BindError
+	assert.Equal(t, errors.As(err, &bindErr), true)
+	var keys []string
+	for _, field := range bindErr.Fields {
+		keys = append(keys, field.Key)
+	}
+	assert.Equal(t, keys, []string{"gole.bind-err.port", "gole.bind-err.timeout", "gole.bind-err.size", "gole.bind-err.name"})
+}
+
+// 测试：绑定之后使用validate进行校验
+func TestBindValidate(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+
+	// 绑定失败的port不再校验，其他属性照常校验，异常的key为属性对应的key
+	_, err := config.Bind[BindCheckCfg]("gole.bind-check")
+	var bindErr *config.BindError
+	assert.Equal(t, errors.As(err, &bindErr), true)
+	assert.Equal(t, bindErr.Fields, []config.BindFieldError{
+		{Key: "gole.bind-check.port", ErrMsg: "值[abc]无法转换为int类型"},
+		{Key: "gole.bind-check.network", ErrMsg: "network值不合法，只可为两个值：tcp和unix"},
+		{Key: "gole.bind-check.retry.times", ErrMsg: "times不能超过10"},
+	})
+}
+
+// 测试：有match标签但是没有注册校验函数时候返回异常
+func TestBindWithoutValidator(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+	config.RegisterBindValidator(nil)
+	defer config.RegisterBindValidator(validate.Check)
+
+	_, err := config.Bind[BindCheckRetry]("gole.bind-check.retry")
+	var bindErr *config.BindError
+	assert.Equal(t, errors.As(err, &bindErr), true)
+	assert.Equal(t, bindErr.Fields[0].Key, "gole.bind-check.retry.times")
+}
+
+// 测试：ByteSize的解析
+func TestParseByteSize(t *testing.T) {
+	size, _ := config.ParseByteSize("1024")
+	assert.Equal(t, size, int64(1024))
+	size, _ = config.ParseByteSize("512kb")
+	assert.Equal(t, size, int64(512*1024))
+	size, _ = config.ParseByteSize("1.5 GiB")
+	assert.Equal(t, size, int64(1536*1024*1024))
+	_, err := config.ParseByteSize("1XB")
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+	"github.com/simonalong/gole/util"
+)
+
+// 测试：并发修改和读取配置，需要使用 go test -race 运行
+func TestConcurrentSetAndGet(t *testing.T) {
+	config.LoadFile("./application.yaml")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(index int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				config.SetValue("concurrent.key"+util.ToString(index), util.ToString(j))
+				config.AppendValue("concurrent.append=" + util.ToString(j))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_ = config.GetValueString("concurrent.key0")
+				_ = config.GetValueInt("key1.key2.intdata")
+				actData := map[string]any{}
+				_ = config.GetValueObject("key1.key2.objdata", &actData)
+				_ = config.GetValueOrigin("key1.key2.strdata")
+				_ = config.GetPropertySources()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 8; i++ {
+		assert.Equal(t, config.GetValueString("concurrent.key"+util.ToString(i)), "49")
+	}
+	assert.Equal(t, config.GetValueString("concurrent.append"), "49")
+	assert.Equal(t, config.GetValueInt("key1.key2.intdata"), 12)
+}
+
+// 测试：并发加载文件和读取配置，读取方不会读到中间状态
+func TestConcurrentLoadAndGet(t *testing.T) {
+	config.LoadFile("./application.yaml")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 50; j++ {
+			config.LoadFile("./application.yaml")
+			config.AppendFile("./application-append.yaml")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 50; j++ {
+			assert.Equal(t, config.GetValueInt("key1.key2.intdata"), 12)
+		}
+	}()
+	wg.Wait()
+}
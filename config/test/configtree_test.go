@@ -0,0 +1,75 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+	"github.com/simonalong/gole/listener"
+)
+
+// 按照Kubernetes的方式挂载目录：文件在版本目录中，..data软链接指向版本目录，每个key软链接到..data下的文件
+func writeConfigTreeVersion(t *testing.T, dir string, version string, values map[string]string) {
+	versionDir := filepath.Join(dir, version)
+	assert.Equal(t, os.MkdirAll(versionDir, 0755), nil)
+	for name, value := range values {
+		assert.Equal(t, os.WriteFile(filepath.Join(versionDir, name), []byte(value), 0644), nil)
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); err != nil {
+			assert.Equal(t, os.Symlink(filepath.Join("..data", name), link), nil)
+		}
+	}
+	// 原子的切换..data
+	assert.Equal(t, os.Symlink(version, filepath.Join(dir, "..data_tmp")), nil)
+	assert.Equal(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")), nil)
+}
+
+// 测试：配置树配置源，以及..data软链接切换后的刷新
+func TestConfigTree(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigTreeVersion(t, dir, "..2024_01", map[string]string{
+		"gole.datasource.password": "pass-1\n",
+		"gole.datasource.username": "root",
+	})
+	assert.Equal(t, os.MkdirAll(filepath.Join(dir, "gole", "server"), 0755), nil)
+	assert.Equal(t, os.WriteFile(filepath.Join(dir, "gole", "server", "port"), []byte("8081"), 0644), nil)
+
+	cfg := config.New()
+	cfg.LoadYamlFile("./application-bind.yaml")
+	changed := make(chan listener.ConfigChangeEvent, 10)
+	cfg.AddChangeListener(func(event listener.ConfigChangeEvent) {
+		changed <- event
+	})
+
+	assert.Equal(t, cfg.AddConfigTree(dir, 20*time.Millisecond), nil)
+	assert.Equal(t, cfg.GetValueString("gole.datasource.password"), "pass-1")
+	assert.Equal(t, cfg.GetValueString("gole.datasource.username"), "root")
+	assert.Equal(t, cfg.GetValueInt("gole.server.port"), 8081)
+	assert.Equal(t, cfg.GetValueOrigin("gole.datasource.password").Source, "configtree:"+dir)
+	for len(changed) > 0 {
+		<-changed
+	}
+
+	writeConfigTreeVersion(t, dir, "..2024_02", map[string]string{
+		"gole.datasource.password": "pass-2",
+		"gole.datasource.username": "root",
+	})
+	select {
+	case event := <-changed:
+		assert.Equal(t, event.Key, "gole.datasource.password")
+		assert.Equal(t, event.Value, "******")
+		assert.Equal(t, cfg.PlainValue(event), "pass-2")
+	case <-time.After(3 * time.Second):
+		t.Fatal("配置树变更后没有发布配置变更事件")
+	}
+	assert.Equal(t, cfg.GetValueString("gole.datasource.password"), "pass-2")
+
+	cfg.RemoveConfigTree(dir)
+	assert.Equal(t, cfg.GetValueString("gole.datasource.password"), "")
+	assert.Equal(t, cfg.GetValueString("gole.bind.name"), "bind-name")
+
+	assert.Equal(t, cfg.AddConfigTree(filepath.Join(dir, "not-exist"), 0) != nil, true)
+}
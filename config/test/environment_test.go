@@ -0,0 +1,42 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+// 测试：环境变量覆盖配置文件
+func TestEnvironmentOverride(t *testing.T) {
+	_ = os.Setenv("GOLE_SERVER_PORT", "9090")
+	_ = os.Setenv("GOLE_DATASOURCE_DRIVERNAME", "postgres")
+	_ = os.Setenv("GOLE_KAFKA_ADDRS_0", "10.0.0.1:9092")
+	_ = os.Setenv("GOLE_ENV_NEW_KEY", "new-value")
+	defer func() {
+		_ = os.Unsetenv("GOLE_SERVER_PORT")
+		_ = os.Unsetenv("GOLE_DATASOURCE_DRIVERNAME")
+		_ = os.Unsetenv("GOLE_KAFKA_ADDRS_0")
+		_ = os.Unsetenv("GOLE_ENV_NEW_KEY")
+	}()
+	config.LoadConfigFromRelativePath("./resources/env")
+
+	assert.Equal(t, config.GetValueInt("gole.server.port"), 9090)
+	assert.Equal(t, config.GetValueString("gole.datasource.driver-name"), "postgres")
+	assert.Equal(t, config.GetValueArrayString("gole.kafka.addrs"), []string{"10.0.0.1:9092", "127.0.0.2:9092"})
+	assert.Equal(t, config.GetValueString("gole.env.new.key"), "new-value")
+}
+
+// 测试：命令行参数覆盖环境变量
+func TestCommandLineOverride(t *testing.T) {
+	_ = os.Setenv("GOLE_SERVER_PORT", "9090")
+	defer os.Unsetenv("GOLE_SERVER_PORT")
+	originalArgs := os.Args
+	os.Args = append([]string{originalArgs[0]}, "--gole.server.port=9191", "--gole.datasource.driverName=sqlite")
+	defer func() { os.Args = originalArgs }()
+	config.LoadConfigFromRelativePath("./resources/env")
+
+	assert.Equal(t, config.GetValueInt("gole.server.port"), 9191)
+	assert.Equal(t, config.GetValueString("gole.datasource.driver-name"), "sqlite")
+}
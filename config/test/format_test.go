@@ -0,0 +1,24 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+// 测试：toml、ini以及.env格式的配置文件
+func TestConfigFormats(t *testing.T) {
+	config.LoadConfigFromRelativePath("./resources/format")
+
+	assert.Equal(t, config.GetValueInt("app.port"), 8080)
+	assert.Equal(t, config.GetValueArrayString("app.hosts"), []string{"h1", "h2"})
+	assert.Equal(t, config.GetValueString("app.servers[1].name"), "s2")
+	// profile对应的ini文件
+	assert.Equal(t, config.GetValueString("app.name"), "ini-dev")
+	assert.Equal(t, config.GetValueString("app.db.url"), "mysql://localhost")
+	// .env优先级高于配置文件
+	assert.Equal(t, config.GetValueString("app.mode"), "local")
+	assert.Equal(t, config.GetValueInt("gole.server.port"), 9090)
+	assert.Equal(t, config.GetValueString("app.password"), "pass # word")
+}
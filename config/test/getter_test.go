@@ -0,0 +1,85 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+func TestGet(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+	config.SetValue("gole.getter.port", "8080")
+	config.SetValue("gole.getter.bad-port", "80a")
+	config.SetValue("gole.getter.enable", "true")
+	config.SetValue("gole.getter.timeout", "1m30s")
+	config.SetValue("gole.getter.max-size", "10MB")
+	config.SetValue("gole.getter.start", "2023-05-01 08:00:00")
+	config.SetValue("gole.getter.addrs", []any{"127.0.0.1:9092", "127.0.0.2:9092"})
+	config.SetValue("gole.getter.ports", "8080, 8081")
+	config.SetValue("gole.getter.url", "http://${gole.getter.host:localhost}:${gole.getter.port}")
+
+	port, err := config.Get[int]("gole.getter.port")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, port, 8080)
+
+	enable, _ := config.Get[bool]("gole.getter.enable")
+	assert.Equal(t, enable, true)
+
+	url, _ := config.Get[string]("gole.getter.url")
+	assert.Equal(t, url, "http://localhost:8080")
+
+	timeout, _ := config.GetDuration("gole.getter.timeout")
+	assert.Equal(t, timeout, 90*time.Second)
+
+	maxSize, _ := config.GetByteSize("gole.getter.max-size")
+	assert.Equal(t, maxSize, config.ByteSize(10<<20))
+
+	start, _ := config.GetTime("gole.getter.start")
+	assert.Equal(t, start, time.Date(2023, 5, 1, 8, 0, 0, 0, time.Local))
+
+	addrs, _ := config.GetSlice[string]("gole.getter.addrs")
+	assert.Equal(t, addrs, []string{"127.0.0.1:9092", "127.0.0.2:9092"})
+
+	ports, _ := config.GetSlice[int]("gole.getter.ports")
+	assert.Equal(t, ports, []int{8080, 8081})
+
+	addr, _ := config.Get[string]("gole.getter.addrs[1]")
+	assert.Equal(t, addr, "127.0.0.2:9092")
+
+	getterMap, _ := config.GetMap("gole.getter")
+	assert.Equal(t, fmt.Sprint(getterMap["port"]), "8080")
+}
+
+func TestGetError(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+	config.SetValue("gole.getter.bad-port", "80a")
+	config.SetValue("gole.getter.bad-timeout", "3x")
+
+	// 配置不存在
+	_, err := config.Get[int]("gole.getter.not-exist")
+	var notFound *config.KeyNotFoundError
+	assert.Equal(t, errors.As(err, &notFound), true)
+	assert.Equal(t, notFound.Key, "gole.getter.not-exist")
+
+	_, exist := config.Lookup("gole.getter.not-exist")
+	assert.Equal(t, exist, false)
+	value, exist := config.Lookup("gole.getter.bad-port")
+	assert.Equal(t, exist, true)
+	assert.Equal(t, value, "80a")
+
+	// 转换失败
+	_, err = config.Get[int]("gole.getter.bad-port")
+	var convertErr *config.ConvertError
+	assert.Equal(t, errors.As(err, &convertErr), true)
+	assert.Equal(t, convertErr.Key, "gole.getter.bad-port")
+	assert.Equal(t, convertErr.Value, "80a")
+	assert.Equal(t, err.Error(), "配置[gole.getter.bad-port]的值[80a]无法转换为int类型")
+
+	_, err = config.GetDuration("gole.getter.bad-timeout")
+	assert.Equal(t, errors.As(err, &convertErr), true)
+	assert.Equal(t, convertErr.Value, "3x")
+}
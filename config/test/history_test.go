@@ -0,0 +1,118 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+	"github.com/simonalong/gole/listener"
+)
+
+// 测试：运行时配置的变更记录以及回滚
+func TestRollback(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+	config.SetValue("gole.history.level", "info")
+	config.SetValue("gole.history.password", "old-pass")
+
+	var events []listener.ConfigChangeEvent
+	listener.AddListener(listener.EventOfConfigChange, func(event listener.GoleEvent) {
+		if ev := event.(listener.ConfigChangeEvent); strings.HasPrefix(ev.Key, "gole.history.") {
+			events = append(events, ev)
+		}
+	})
+
+	history := config.GetChangeHistory()
+	version := history[len(history)-1].Version + 1
+	config.SetValue("gole.history.level", "trace")
+	config.SetValue("gole.history.password", "new-pass")
+	config.AppendValue("gole.history.added=true")
+
+	history = config.GetChangeHistory()
+	last := history[len(history)-1]
+	assert.Equal(t, last.Version, version+2)
+	assert.Equal(t, last.Key, "gole.history.added")
+	assert.Equal(t, last.OldValue, nil)
+	assert.Equal(t, last.Source, config.ChangeSourceCode)
+	levelRecord := history[len(history)-3]
+	assert.Equal(t, levelRecord.Key, "gole.history.level")
+	assert.Equal(t, levelRecord.OldValue, "info")
+	assert.Equal(t, levelRecord.NewValue, "trace")
+	// 脱敏的配置
+	assert.Equal(t, history[len(history)-2].OldValue, "******")
+
+	events = nil
+	assert.Equal(t, config.Rollback(version), nil)
+	assert.Equal(t, config.GetValueString("gole.history.level"), "info")
+	assert.Equal(t, config.GetValueString("gole.history.password"), "old-pass")
+	assert.Equal(t, config.GetValueString("gole.history.added"), "")
+	assert.Equal(t, len(events), 3)
+
+	// 回滚也是一个新的版本
+	history = config.GetChangeHistory()
+	assert.Equal(t, history[len(history)-1].Source, config.ChangeSourceRollback)
+	assert.Equal(t, history[len(history)-1].Version, version+3)
+
+	assert.Equal(t, config.Rollback(version+100) != nil, true)
+}
+
+// 测试：变更记录的持久化
+func TestHistoryFile(t *testing.T) {
+	dir := t.TempDir()
+	historyFile := filepath.Join(dir, "history.json")
+	_ = os.WriteFile(filepath.Join(dir, "application.yaml"), []byte("gole:\n  config:\n    history:\n      max-size: 2\n      file: "+historyFile+"\n"), 0644)
+
+	config.LoadConfigFromAbsPath(dir)
+	config.SetValue("gole.history-file.k1", "v1")
+	config.SetValue("gole.history-file.k2", "v2")
+	config.SetValue("gole.history-file.k3", "v3")
+	history := config.GetChangeHistory()
+	assert.Equal(t, len(history), 2)
+	assert.Equal(t, history[1].Key, "gole.history-file.k3")
+
+	// 重新加载后恢复变更记录，但是不能回滚
+	config.LoadConfigFromAbsPath(dir)
+	history = config.GetChangeHistory()
+	assert.Equal(t, len(history), 2)
+	assert.Equal(t, history[1].Key, "gole.history-file.k3")
+	assert.Equal(t, config.Rollback(history[1].Version) != nil, true)
+}
+
+// 测试：加载配置时候写入的激活profile不作为变更记录
+func TestHistoryActiveProfile(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "application.yaml"), []byte("gole:\n  profiles:\n    active: dev\n"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "application-dev.yaml"), []byte("gole:\n  history-profile: dev\n"), 0644)
+
+	cfg := config.New()
+	cfg.LoadConfigFromAbsPath(dir)
+	cfg.LoadConfigFromAbsPath(dir)
+	assert.Equal(t, cfg.GetValueString("gole.profiles.active"), "dev")
+	assert.Equal(t, cfg.GetValueString("gole.history-profile"), "dev")
+	assert.Equal(t, len(cfg.GetChangeHistory()), 0)
+}
+
+// 测试：变更记录的文件以及端点的配置不能通过/config/update修改
+func TestHistoryFileProtected(t *testing.T) {
+	dir := t.TempDir()
+	historyFile := filepath.Join(dir, "history.json")
+	config.LoadFile("./application-bind.yaml")
+	// 允许所有的key也不能修改gole.config.*以及gole.endpoint.*
+	config.SetValue("gole.endpoint.config.update.allow-keys", "*")
+
+	for _, body := range []string{
+		`{"key":"gole.config.history.file", "value":"` + historyFile + `"}`,
+		`{"key":"gole.config.history.maxSize", "value":"1"}`,
+		`{"key":"gole.endpoint.config.update.allow-keys", "value":"*"}`,
+		`{"key":"gole.endpoint.config.mask.patterns", "value":"none"}`,
+	} {
+		assert.Equal(t, requestEndpointRecorder("PUT", "/config/update", body).Code, 403, body)
+	}
+	requestEndpoint("PUT", "/config/update", `{"key":"gole.history-protected.k1", "value":"v1"}`)
+	assert.Equal(t, config.GetValueString("gole.history-protected.k1"), "v1")
+	assert.Equal(t, config.GetValueString("gole.config.history.file"), "")
+	_, err := os.Stat(historyFile)
+	assert.Equal(t, os.IsNotExist(err), true)
+}
@@ -0,0 +1,37 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+// 测试：gole.config.import 导入其他配置文件
+func TestConfigImport(t *testing.T) {
+	config.LoadFile("./resources/import/application.yaml")
+
+	// 导入的文件优先级高于导入它的文件，后导入的优先级高
+	assert.Equal(t, config.GetValueString("app.db"), "shared-db")
+	assert.Equal(t, config.GetValueString("app.name"), "common")
+	assert.Equal(t, config.GetValueString("app.common"), "common")
+	// 目录下的文件按照文件名排序导入
+	assert.Equal(t, config.GetValueString("app.conf"), "conf-b")
+	assert.Equal(t, config.GetValueString("app.conf-a"), "a")
+	// 循环导入被忽略
+	assert.Equal(t, config.GetValueString("app.cycle"), "cycle")
+	assert.Equal(t, config.GetValue("gole.config.import"), nil)
+
+	var names []string
+	for _, source := range config.GetPropertySources() {
+		names = append(names, source.Name)
+	}
+	assert.Equal(t, names, []string{
+		"resources/import/cycle.yaml",
+		"resources/import/conf.d/b.properties",
+		"resources/import/conf.d/a.yaml",
+		"resources/import/shared/common.properties",
+		"resources/import/shared/db.yml",
+		"./resources/import/application.yaml",
+	})
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+	"github.com/simonalong/gole/listener"
+)
+
+// 测试：多个配置实例之间互相独立
+func TestConfigInstance(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+
+	cfg := config.New()
+	cfg.LoadYamlFile("./application-bind.yaml")
+	assert.Equal(t, cfg.GetValueString("gole.bind.name"), "bind-name")
+
+	var events []listener.ConfigChangeEvent
+	removeListener := cfg.AddChangeListener(func(event listener.ConfigChangeEvent) {
+		events = append(events, event)
+	})
+	cfg.SetValue("gole.bind.name", "instance-name")
+	assert.Equal(t, cfg.GetValueString("gole.bind.name"), "instance-name")
+	assert.Equal(t, config.GetValueString("gole.bind.name"), "bind-name")
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, len(cfg.GetChangeHistory()), 1)
+
+	// 移除监听后不再通知
+	removeListener()
+	cfg.SetValue("gole.bind.name", "instance-name2")
+	assert.Equal(t, len(events), 1)
+
+	timeout, err := cfg.GetDuration("gole.bind.connect-timeout")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, timeout.String(), "5s")
+	servers, err := config.GetSliceFrom[string](cfg, "gole.bind.servers")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, servers, []string{"127.0.0.1:1883", "127.0.0.2:1883"})
+
+	watcher, err := config.WatchFrom[BindCfg](cfg, "gole.bind")
+	assert.Equal(t, err, nil)
+	cfg.SetValue("gole.bind.retry.times", 5)
+	assert.Equal(t, watcher.Get().Retry.Times, 5)
+	assert.Equal(t, config.GetValueInt("gole.bind.retry.times"), 3)
+
+	assert.Equal(t, config.Default().GetValueString("gole.bind.name"), "bind-name")
+}
+
+// 测试：WithOverrides在测试结束后恢复之前的配置
+func TestWithOverrides(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+	historySize := len(config.GetChangeHistory())
+
+	t.Run("overrides", func(t *testing.T) {
+		config.WithOverrides(t, map[string]any{
+			"gole.bind.name":  "override-name",
+			"gole.bind.retry": map[string]any{"times": 9},
+		})
+		assert.Equal(t, config.GetValueString("gole.bind.name"), "override-name")
+		assert.Equal(t, config.GetValueInt("gole.bind.retry.times"), 9)
+
+		config.SetValue("gole.bind.max-size", "1MB")
+		assert.Equal(t, config.GetValueString("gole.bind.max-size"), "1MB")
+	})
+
+	assert.Equal(t, config.GetValueString("gole.bind.name"), "bind-name")
+	assert.Equal(t, config.GetValueInt("gole.bind.retry.times"), 3)
+	assert.Equal(t, config.GetValueString("gole.bind.max-size"), "10MB")
+	assert.Equal(t, len(config.GetChangeHistory()), historySize)
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+type RedisKeyCfg struct {
+	PoolSize     int
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	Nodes        []RedisNodeCfg
+}
+
+type RedisNodeCfg struct {
+	Host        string
+	ReadTimeout string
+}
+
+// 测试：中划线、驼峰、下划线的写法对应同一个配置
+func TestRelaxedKey(t *testing.T) {
+	cfg := config.New()
+	cfg.LoadYamlFile("./resources/key/application.yaml")
+	cfg.AppendYamlFile("./resources/key/application-prod.yaml")
+
+	// 不同配置源中的不同写法合并为一个配置，规范的写法为优先级最低的配置源中的写法
+	for _, key := range []string{"gole.redis.pool-size", "gole.redis.poolSize", "gole.redis.pool_size", "GOLE.REDIS.POOL_SIZE"} {
+		assert.Equal(t, cfg.GetValueInt(key), 20)
+	}
+	assert.Equal(t, cfg.GetValueInt("gole.redis.maxIdleConns"), 8)
+	assert.Equal(t, cfg.GetValueBool("gole.kafka.sasl-handshake"), true)
+	assert.Equal(t, cfg.GetValueString("gole.redis.nodes[0].readTimeout"), "3s")
+	origin := cfg.GetValueOrigin("gole.redis.poolSize")
+	assert.Equal(t, origin.Key, "gole.redis.pool-size")
+	assert.Equal(t, len(origin.Overridden), 1)
+
+	// 绑定
+	redisCfg, err := config.BindFrom[RedisKeyCfg](cfg, "gole.Redis")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, redisCfg.PoolSize, 20)
+	assert.Equal(t, redisCfg.MaxIdleConns, 8)
+	assert.Equal(t, redisCfg.Nodes[0].ReadTimeout, "3s")
+
+	// GetValueObject的匹配同绑定
+	objectCfg := RedisKeyCfg{}
+	assert.Equal(t, cfg.GetValueObject("gole.redis", &objectCfg), nil)
+	assert.Equal(t, objectCfg.PoolSize, 20)
+	assert.Equal(t, objectCfg.MaxIdleConns, 8)
+	assert.Equal(t, objectCfg.Nodes[0].ReadTimeout, "3s")
+
+	// SetValue修改的是同一个配置
+	cfg.SetValue("gole.redis.pool_size", 30)
+	assert.Equal(t, cfg.GetValueInt("gole.redis.pool-size"), 30)
+	assert.Equal(t, cfg.GetValueOrigin("gole.redis.pool-size").Source, config.SourceTypeRuntime)
+	value, exist := cfg.Lookup("gole.redis.poolSize")
+	assert.Equal(t, exist, true)
+	assert.Equal(t, value, 30)
+	assert.Equal(t, cfg.GetChangeHistory()[0].Key, "gole.redis.pool-size")
+
+	// 不存在的配置保持原始写法
+	cfg.SetValue("gole.redis.newKey", "a")
+	assert.Equal(t, cfg.GetValueString("gole.redis.new-key"), "a")
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+// 测试：叠加配置文件时候数组的合并方式
+func TestListMerge(t *testing.T) {
+	cfg := config.New()
+	cfg.LoadYamlFile("./resources/merge/application.yaml")
+	cfg.AppendYamlFile("./resources/merge/application-prod.yaml")
+
+	// 默认整体替换，多余的下标从扁平化以及深层的配置中都删除
+	assert.Equal(t, cfg.GetValueArrayString("gole.kafka.addrs"), []string{"192.168.0.1:9092"})
+	_, exist := cfg.Lookup("gole.kafka.addrs[1]")
+	assert.Equal(t, exist, false)
+	assert.Equal(t, cfg.GetValueString("gole.kafka.addrs[2]"), "")
+	assert.Equal(t, cfg.GetValueOrigin("gole.kafka.addrs[1]") == nil, true)
+
+	// 追加
+	assert.Equal(t, cfg.GetValueArrayString("gole.kafka.brokers"), []string{"broker-1", "broker-2", "broker-3"})
+	origin := cfg.GetValueOrigin("gole.kafka.brokers[0]")
+	assert.Equal(t, origin.Value, "broker-1")
+	assert.Equal(t, len(origin.Overridden), 0)
+
+	// 按照name合并：相同name的元素合并字段，元素中的数组整体替换，其他的追加
+	assert.Equal(t, cfg.GetValueString("gole.datasource.list[0].host"), "10.0.0.1")
+	assert.Equal(t, cfg.GetValueString("gole.datasource.list[0].tags[1]"), "b")
+	assert.Equal(t, cfg.GetValueString("gole.datasource.list[1].name"), "slave")
+	assert.Equal(t, cfg.GetValueString("gole.datasource.list[1].host"), "192.168.0.2")
+	assert.Equal(t, cfg.GetValueString("gole.datasource.list[1].tags[0]"), "c")
+	_, exist = cfg.Lookup("gole.datasource.list[1].tags[1]")
+	assert.Equal(t, exist, false)
+	assert.Equal(t, cfg.GetValueString("gole.datasource.list[2].name"), "backup")
+	list, err := config.GetSliceFrom[map[string]any](cfg, "gole.datasource.list")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list), 3)
+}
+
+// 测试：AppendValue、SetValue中的数组同样按照合并方式处理
+func TestListMergeRuntime(t *testing.T) {
+	cfg := config.New()
+	cfg.LoadYamlFile("./resources/merge/application.yaml")
+
+	cfg.AppendValue("gole.kafka.addrs[0]=10.0.0.1:9092")
+	assert.Equal(t, cfg.GetValueArrayString("gole.kafka.addrs"), []string{"10.0.0.1:9092"})
+
+	cfg.SetValue("gole.kafka.addrs", []string{"10.0.0.2:9092", "10.0.0.3:9092"})
+	assert.Equal(t, cfg.GetValueArrayString("gole.kafka.addrs"), []string{"10.0.0.2:9092", "10.0.0.3:9092"})
+	cfg.SetValue("gole.kafka.addrs", []string{"10.0.0.4:9092"})
+	assert.Equal(t, cfg.GetValueArrayString("gole.kafka.addrs"), []string{"10.0.0.4:9092"})
+
+	cfg.AppendValue("gole.kafka.brokers[0]=broker-4")
+	assert.Equal(t, cfg.GetValueArrayString("gole.kafka.brokers"), []string{"broker-1", "broker-4"})
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+// 测试：注册过元数据的模块下，未知的配置以及废弃的配置会有提示
+func TestCheckKeys(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+	config.RegisterKeyMetadata(
+		config.KeyMetadata{Key: "gole.metatest.port", Type: "int", Default: "8080", Description: "端口"},
+		config.KeyMetadata{Key: "gole.metatest.addrs", Type: "list"},
+		config.KeyMetadata{Key: "gole.metatest.group.*.level", Type: "string"},
+		config.KeyMetadata{Key: "gole.metatest.labels", Type: "map"},
+		config.KeyMetadata{Key: "gole.metatest.dir", Type: "string", Deprecated: true, Replacement: "gole.metatest.home"},
+		config.KeyMetadata{Key: "gole.metatest.home", Type: "string"},
+	)
+
+	config.SetValue("gole.metatest.prot", 8081)
+	config.SetValue("gole.metatest.addrs[0]", "127.0.0.1")
+	config.SetValue("gole.metatest.group.orm.level", "debug")
+	config.SetValue("gole.metatest.labels.app.name", "demo")
+	config.SetValue("gole.metatest.dir", "./logs")
+	// 宽松匹配
+	config.SetValue("gole.metatest.HOME", "./logs")
+	// 没有注册元数据的模块不检查
+	config.SetValue("gole.unknown-module.key", "value")
+	config.SetValue("gole.emqx.metatest", "value")
+	// 模块名拼写错误
+	config.SetValue("gole.metatset.port", 8081)
+	config.SetValue("gole.sever.metatest-port", 8081)
+
+	var warnings []string
+	for _, warning := range config.CheckKeys() {
+		if strings.Contains(warning, "metatest") || strings.Contains(warning, "metatset") || strings.Contains(warning, "gole.unknown-module") {
+			warnings = append(warnings, warning)
+		}
+	}
+	assert.Equal(t, warnings, []string{
+		"配置[gole.metatest.dir]已废弃，请使用：gole.metatest.home",
+		"配置[gole.metatest.prot]不是已知的配置，是否为：gole.metatest.port",
+		"配置[gole.metatset.port]不是已知的配置，是否为：gole.metatest.port",
+		"配置[gole.sever.metatest-port]不是已知的配置，是否为：gole.server.metatest-port",
+	})
+}
+
+func TestConfigMetadata(t *testing.T) {
+	config.RegisterKeyMetadata(config.KeyMetadata{Key: "gole.metatest.timeout", Type: "duration", Default: "3s", Description: "超时时间"})
+
+	var metadata config.KeyMetadata
+	for _, item := range config.GetKeyMetadata() {
+		if item.Key == "gole.metatest.timeout" {
+			metadata = item
+		}
+	}
+	assert.Equal(t, metadata.Default, "3s")
+	assert.Equal(t, strings.Contains(requestMetadata(), `"key":"gole.metatest.timeout"`), true)
+}
+
+func requestMetadata() string {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/config/metadata", config.GetConfigMetadata)
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest("GET", "/config/metadata", nil))
+	return recorder.Body.String()
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+// 测试：占位符的嵌入、默认值、嵌套
+func TestPlaceHolderEmbed(t *testing.T) {
+	config.LoadFile("./application-place2.yaml")
+
+	assert.Equal(t, config.GetValueString("test.url"), "http://localhost:8080/api")
+	assert.Equal(t, config.GetValueString("test.default"), "default-value")
+	assert.Equal(t, config.GetValueString("test.default-empty"), "")
+	assert.Equal(t, config.GetValueString("test.default-nested"), "test")
+	assert.Equal(t, config.GetValueString("test.nested"), "test")
+	assert.Equal(t, config.GetValueString("place.inner.name"), "test")
+}
+
+// 测试：占位符读取环境变量
+func TestPlaceHolderEnv(t *testing.T) {
+	_ = os.Setenv("GOLE_PLACE_ENV_VALUE", "env-value")
+	defer os.Unsetenv("GOLE_PLACE_ENV_VALUE")
+	config.LoadFile("./application-place2.yaml")
+
+	assert.Equal(t, config.GetValueString("test.env"), "env-value")
+	assert.Equal(t, config.GetValueString("test.env-relaxed"), "env-value")
+}
+
+// 测试：占位符引用非叶子节点
+func TestPlaceHolderObject(t *testing.T) {
+	config.LoadFile("./application-place2.yaml")
+
+	actData := map[string]any{}
+	err := config.GetValueObject("test.obj", &actData)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, actData["name"], "test")
+}
+
+// 测试：占位符无法解析以及循环引用
+func TestPlaceHolderError(t *testing.T) {
+	config.LoadFile("./application-place2.yaml")
+
+	assert.Equal(t, config.GetValueString("test.unresolved"), "${place.none}")
+	_, err := config.ResolvePlaceholders("${test.unresolved}")
+	placeholderErr, ok := err.(*config.PlaceholderError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, placeholderErr.Key, "place.none")
+	assert.Equal(t, placeholderErr.Cycle, false)
+
+	assert.Equal(t, config.GetValueString("test.cycle1"), "${test.cycle2}")
+	_, err = config.ResolvePlaceholders("${test.cycle1}")
+	placeholderErr, ok = err.(*config.PlaceholderError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, placeholderErr.Cycle, true)
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+// 测试：多个profile、profile分组以及多文档yaml
+func TestMultipleProfiles(t *testing.T) {
+	config.LoadConfigFromRelativePath("./resources/profile")
+
+	assert.Equal(t, config.GetActiveProfiles(), []string{"prod", "prod-db", "prod-mq", "eu"})
+	assert.Equal(t, config.CurrentProfile, "prod,prod-db,prod-mq,eu")
+	// 后激活的profile优先级高
+	assert.Equal(t, config.GetValueString("app.name"), "eu")
+	assert.Equal(t, config.GetValueString("app.db"), "prod-db")
+	assert.Equal(t, config.GetValueString("app.mq"), "prod-mq")
+	// 多文档yaml中满足条件的文档生效
+	assert.Equal(t, config.GetValueString("app.region"), "eu-doc")
+	assert.Equal(t, config.GetValueString("app.mode"), "not-test")
+	assert.Equal(t, config.GetValue("gole.config.activate.on-profile"), nil)
+}
+
+// 测试：profile名字中带有中划线，比如：application-prod-eu.yaml
+func TestProfileWithDash(t *testing.T) {
+	originalArgs := os.Args
+	os.Args = append([]string{originalArgs[0]}, "--gole.profiles.active=test,prod-eu")
+	defer func() { os.Args = originalArgs }()
+	config.LoadConfigFromRelativePath("./resources/profile")
+
+	assert.Equal(t, config.GetActiveProfiles(), []string{"test", "prod-eu"})
+	assert.Equal(t, config.GetValueString("app.name"), "prod-eu")
+	assert.Equal(t, config.GetValueString("app.region"), "none")
+	assert.Equal(t, config.GetValueString("app.mode"), "")
+	assert.Equal(t, config.GetValueString("app.db"), "none")
+}
+
+type DateCfg struct {
+	Date string
+	Time string
+}
+
+// 测试：yaml中的时间读取为原始的字符串
+func TestYamlTimestampValue(t *testing.T) {
+	cfg := config.New()
+	cfg.LoadYamlFile("./resources/profile/timestamp.yaml")
+
+	assert.Equal(t, cfg.GetValueString("app.date"), "2024-01-02")
+	assert.Equal(t, cfg.GetValue("app.time"), "2024-01-02T15:04:05Z")
+	dateCfg := DateCfg{}
+	assert.Equal(t, cfg.GetValueObject("app", &dateCfg), nil)
+	assert.Equal(t, dateCfg, DateCfg{Date: "2024-01-02", Time: "2024-01-02T15:04:05Z"})
+}
+
+// 测试：yaml中重复的key不会导致整个文件失效，后面的值覆盖前面的值
+func TestYamlDuplicateKey(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "application.yaml"), []byte("a: {b: 1}\na: {c: 2}\nx: y\n"), 0644)
+
+	cfg := config.New()
+	cfg.LoadConfigFromAbsPath(dir)
+	assert.Equal(t, cfg.GetValueString("x"), "y")
+	assert.Equal(t, cfg.GetValueInt("a.c"), 2)
+	assert.Equal(t, cfg.GetValueString("a.b"), "")
+}
+
+// 测试：解析失败的配置文件打印文件路径以及出错的位置
+func TestYamlParseErrorLogged(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "application.yaml"), []byte("a: 1\nb: c: d\n"), 0644)
+
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	cfg := config.New()
+	cfg.LoadConfigFromAbsPath(dir)
+	assert.Equal(t, cfg.GetValueString("a"), "")
+	assert.Equal(t, strings.Contains(output.String(), "解析配置文件["+filepath.Join(dir, "application.yaml")+"]失败(yaml: line 2, column 5"), true)
+}
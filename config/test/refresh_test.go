@@ -0,0 +1,55 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+)
+
+type RefreshLimit struct {
+	Qps     int           `yaml:"qps" default:"100"`
+	Timeout time.Duration `yaml:"timeout" default:"1s"`
+}
+
+// 测试：前缀下的配置变更后自动重新绑定，并通知回调
+func TestWatch(t *testing.T) {
+	config.LoadFile("./application-bind.yaml")
+
+	limit, err := config.Watch[RefreshLimit]("gole.refresh.limit")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, limit.Get(), RefreshLimit{Qps: 100, Timeout: time.Second})
+
+	var oldValues, newValues []RefreshLimit
+	limit.OnChange(func(oldValue, newValue RefreshLimit) {
+		oldValues = append(oldValues, oldValue)
+		newValues = append(newValues, newValue)
+	})
+
+	config.SetValue("gole.refresh.limit.qps", 200)
+	assert.Equal(t, limit.Get().Qps, 200)
+	assert.Equal(t, oldValues, []RefreshLimit{{Qps: 100, Timeout: time.Second}})
+	assert.Equal(t, newValues, []RefreshLimit{{Qps: 200, Timeout: time.Second}})
+
+	// 其他前缀的变更不会触发
+	config.SetValue("gole.refresh.other", "x")
+	assert.Equal(t, len(newValues), 1)
+
+	// AppendValue同样会触发
+	config.AppendValue("gole.refresh.limit.timeout=3s")
+	assert.Equal(t, limit.Get().Timeout, 3*time.Second)
+	assert.Equal(t, len(newValues), 2)
+
+	// 绑定失败则保留之前的值
+	config.SetValue("gole.refresh.limit.qps", "abc")
+	assert.Equal(t, limit.Get().Qps, 200)
+	assert.Equal(t, len(newValues), 2)
+
+	// 关闭后不再自动刷新，保留最后一次绑定的值
+	limit.Close()
+	limit.Close()
+	config.SetValue("gole.refresh.limit.qps", 300)
+	assert.Equal(t, limit.Get().Qps, 200)
+	assert.Equal(t, len(newValues), 2)
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+	"github.com/simonalong/gole/util"
+)
+
+// 测试：配置来源以及被覆盖的值
+func TestValueOrigin(t *testing.T) {
+	config.LoadFile("./application-append-original.yaml")
+	config.AppendFile("./application-append.yaml")
+
+	origin := config.GetValueOrigin("a.b.c")
+	assert.Equal(t, origin.Value, "c-value-change")
+	assert.Equal(t, origin.Source, "./application-append.yaml")
+	assert.Equal(t, len(origin.Overridden), 1)
+	assert.Equal(t, origin.Overridden[0].Source, "./application-append-original.yaml")
+	assert.Equal(t, origin.Overridden[0].Value, "c-value")
+
+	origin = config.GetValueOrigin("a.b.d")
+	assert.Equal(t, origin.Source, "./application-append.yaml")
+	assert.Equal(t, len(origin.Overridden), 0)
+
+	assert.Equal(t, config.GetValueOrigin("a.b.none") == nil, true)
+}
+
+// 测试：运行时修改的配置优先级最高
+func TestRuntimeSource(t *testing.T) {
+	config.LoadFile("./application-append-original.yaml")
+	config.SetValue("a.b.c", "c-value-runtime")
+	config.AppendFile("./application-append.yaml")
+
+	assert.Equal(t, config.GetValueString("a.b.c"), "c-value-runtime")
+	origin := config.GetValueOrigin("a.b.c")
+	assert.Equal(t, origin.Source, config.SourceTypeRuntime)
+	assert.Equal(t, len(origin.Overridden), 2)
+	assert.Equal(t, origin.Overridden[0].Value, "c-value-change")
+
+	sources := config.GetPropertySources()
+	assert.Equal(t, len(sources), 3)
+	assert.Equal(t, sources[0].Type, config.SourceTypeRuntime)
+	assert.Equal(t, sources[1].Name, "./application-append.yaml")
+	assert.Equal(t, sources[2].Name, "./application-append-original.yaml")
+}
+
+type QuotedValueCfg struct {
+	Code   string
+	Ver    string
+	Flag   string
+	Mode   string
+	Port   int
+	Rate   float64
+	Enable bool
+}
+
+// 测试：yaml中加引号的值保持为字符串，不会被推断为数字或者布尔
+func TestQuotedValueType(t *testing.T) {
+	cfg := config.New()
+	cfg.LoadYamlFile("./resources/source/application.yaml")
+
+	assert.Equal(t, cfg.GetValue("app.code"), "0123")
+	assert.Equal(t, cfg.GetValue("app.ver"), "1.10")
+	assert.Equal(t, cfg.GetValue("app.flag"), "yes")
+	assert.Equal(t, cfg.GetValue("app.mode"), "on")
+	assert.Equal(t, cfg.GetValue("app.port"), 8080)
+	assert.Equal(t, cfg.GetValue("app.enable"), true)
+	assert.Equal(t, cfg.GetValueString("app.code"), "0123")
+
+	value, err := config.GetFrom[string](cfg, "app.ver")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, value, "1.10")
+
+	quotedCfg := QuotedValueCfg{}
+	assert.Equal(t, cfg.GetValueObject("app", &quotedCfg), nil)
+	assert.Equal(t, quotedCfg, QuotedValueCfg{Code: "0123", Ver: "1.10", Flag: "yes", Mode: "on", Port: 8080, Rate: 1.5, Enable: true})
+
+	boundCfg, err := config.BindFrom[QuotedValueCfg](cfg, "app")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, boundCfg, quotedCfg)
+}
+
+// 测试：查看配置来源的接口
+func TestValueOriginEndpoint(t *testing.T) {
+	config.LoadFile("./application-append-original.yaml")
+	config.AppendFile("./application-append.yaml")
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/config/value/:key", config.GetConfigValue)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest("GET", "/config/value/a.b.c?origin=true", nil))
+
+	origin := map[string]any{}
+	assert.Equal(t, util.DataToObject(recorder.Body.String(), &origin), nil)
+	assert.Equal(t, origin["key"], "a.b.c")
+	assert.Equal(t, origin["value"], "c-value-change")
+	assert.Equal(t, origin["source"], "./application-append.yaml")
+	overridden := origin["overridden"].([]any)
+	assert.Equal(t, len(overridden), 1)
+	assert.Equal(t, overridden[0].(map[string]any)["source"], "./application-append-original.yaml")
+
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest("GET", "/config/value/a.b.none?origin=true", nil))
+	assert.Equal(t, recorder.Body.String(), "{}")
+}
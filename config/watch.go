@@ -0,0 +1,135 @@
+package config
+
+import (
+	"log"
+	"os"
+	"reflect"
+	"time"
+)
+
+// 配置文件的状态，用于判断文件是否有变更
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// StartWatchFiles 开启已加载配置文件（包括profile和additional-location对应的文件）的监听，interval为检查间隔；
+// 文件变更后会重新加载，并对变更、新增以及删除的key发布配置变更事件
+// 也可以通过配置开启：gole.config.watch.enable=true，gole.config.watch.interval=5s
+func StartWatchFiles(interval time.Duration) {
+	defaultConfig.StartWatchFiles(interval)
+}
+
+// StartWatchFiles 开启已加载配置文件的监听，interval为检查间隔；文件变更后会重新加载，并对变更、新增以及删除的key发布配置变更事件
+func (c *Config) StartWatchFiles(interval time.Duration) {
+	c.watchLock.Lock()
+	defer c.watchLock.Unlock()
+	if c.watchStopChan != nil {
+		return
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	stopChan := make(chan struct{})
+	c.watchStopChan = stopChan
+	states := c.fileStates()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopChan:
+				return
+			case <-ticker.C:
+				newStates := c.fileStates()
+				var changedFiles []string
+				for filePath, state := range newStates {
+					if oldState, exist := states[filePath]; !exist || oldState != state {
+						changedFiles = append(changedFiles, filePath)
+					}
+				}
+				states = newStates
+				if len(changedFiles) > 0 {
+					c.reloadFileSources(changedFiles)
+				}
+			}
+		}
+	}()
+}
+
+// StopWatchFiles 停止配置文件的监听
+func StopWatchFiles() {
+	defaultConfig.StopWatchFiles()
+}
+
+// StopWatchFiles 停止配置文件的监听
+func (c *Config) StopWatchFiles() {
+	c.watchLock.Lock()
+	defer c.watchLock.Unlock()
+	if c.watchStopChan != nil {
+		close(c.watchStopChan)
+		c.watchStopChan = nil
+	}
+}
+
+// ReloadFiles 重新读取所有已加载的配置文件，并对变更的key发布配置变更事件
+func ReloadFiles() {
+	defaultConfig.ReloadFiles()
+}
+
+// ReloadFiles 重新读取所有已加载的配置文件，并对变更的key发布配置变更事件
+func (c *Config) ReloadFiles() {
+	property := c.currentProperty()
+	if nil == property {
+		return
+	}
+	var filePaths []string
+	for _, source := range property.Sources {
+		if source.parser != nil {
+			filePaths = append(filePaths, source.Name)
+		}
+	}
+	c.reloadFileSources(filePaths)
+}
+
+// 重新加载对应的文件配置源，文件不存在或者解析失败的时候保留之前的配置
+func (c *Config) reloadFileSources(filePaths []string) {
+	oldProperty, newProperty := c.updatePropertySources(func(sources []*PropertySource) []*PropertySource {
+		for index, source := range sources {
+			if source.parser == nil || !containsKey(filePaths, source.Name) {
+				continue
+			}
+			valueMap, ok := readFileSource(source.Name, source.parser)
+			if !ok {
+				log.Printf("重新加载配置文件[%s]失败，保留之前的配置", source.Name)
+				continue
+			}
+			takeImportLocations(valueMap)
+			if reflect.DeepEqual(valueMap, source.ValueMap) {
+				continue
+			}
+			sources[index] = &PropertySource{Name: source.Name, Type: source.Type, ValueMap: valueMap, parser: source.parser}
+		}
+		return sources
+	})
+	c.publishChangeEvents(oldProperty, newProperty)
+}
+
+// 当前所有文件配置源的状态
+func (c *Config) fileStates() map[string]fileState {
+	states := map[string]fileState{}
+	property := c.currentProperty()
+	if nil == property {
+		return states
+	}
+	for _, source := range property.Sources {
+		if source.parser == nil {
+			continue
+		}
+		if info, err := os.Stat(source.Name); err == nil {
+			states[source.Name] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return states
+}
@@ -33,6 +33,8 @@ func Help(c *gin.Context) {
 	cmdMap["6.2 服务所有配置(yaml结构)"] = "-------: " + "curl http://localhost:" + pre(port) + "/config/values/yaml"
 	cmdMap["6.3 服务某个配置"] = "----------------: " + "curl http://localhost:" + pre(port) + "/config/value/{key}"
 	cmdMap["6.4 修改服务的配置"] = "--------------: " + "curl -X PUT http://localhost:" + pre(port) + "/config/update -d '{\"key\":\"xxx\", \"value\":\"yyy\"}'"
+	cmdMap["6.5 服务支持的配置"] = "--------------: " + "curl http://localhost:" + pre(port) + "/config/metadata"
+	cmdMap["6.6 配置的变更记录"] = "--------------: " + "curl http://localhost:" + pre(port) + "/config/history"
 	cmdMap["==============================================================================================================================================================================================="] = ""
 
 	rsp.Success(c, cmdMap)
@@ -0,0 +1,257 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/simonalong/gole/config"
+	"github.com/simonalong/gole/logger"
+	etcdClientV3 "go.etcd.io/etcd/client/v3"
+)
+
+// 默认的配置前缀，{app}为gole.application.name，{profile}为激活的profile（多个profile时候每个profile一个配置源，没有激活则为default）
+const defaultConfigPrefix = "/config/{app}/{profile}/"
+
+// 默认的本地缓存文件，etcd不可用时候使用
+const defaultConfigCacheFile = "./config/etcd-config-cache.json"
+
+// 读取etcd的超时时间
+const configReadTimeout = 5 * time.Second
+
+// 重新连接etcd的间隔
+const configRetryInterval = 5 * time.Second
+
+// ConfigSource etcd配置源：读取前缀下的所有key作为配置（优先级高于本地配置文件），并监听前缀下key的变更
+//   - /config/app/prod/gole.server.port -> gole.server.port
+//   - /config/app/prod/gole/server/port -> gole.server.port
+type ConfigSource struct {
+	client    *etcdClientV3.Client
+	prefixes  []string
+	cacheFile string
+	cancel    context.CancelFunc
+	// 监听的协程，关闭的时候等待其退出
+	watchers sync.WaitGroup
+
+	// 每个前缀当前的配置，用于写本地缓存
+	lock      sync.Mutex
+	valueMaps map[string]map[string]any
+	// 多个前缀的监听协程同时写本地缓存时候串行写入，避免旧的内容覆盖新的内容
+	cacheLock sync.Mutex
+}
+
+// 启动时候按照配置加载的配置源
+var defaultConfigSource *ConfigSource
+
+// LoadConfigSource 按照配置加载etcd的配置源
+//
+//	gole:
+//	  etcd:
+//	    config:
+//	      enable: true
+//	      prefix: /config/{app}/{profile}/
+//	      cache-file: ./config/etcd-config-cache.json
+func LoadConfigSource() (*ConfigSource, error) {
+	etcdClient, err := NewEtcdClient()
+	if err != nil {
+		return nil, err
+	}
+	if etcdClient == nil {
+		return nil, nil
+	}
+	prefix := config.GetValueStringDefault("gole.etcd.config.prefix", defaultConfigPrefix)
+	cacheFile := config.GetValueStringDefault("gole.etcd.config.cache-file", defaultConfigCacheFile)
+	return NewConfigSource(etcdClient.Client, prefix, cacheFile), nil
+}
+
+// NewConfigSource 读取prefix下的配置作为配置源，并开始监听；etcd不可用的时候使用本地缓存文件cacheFile中的配置，并在后台重试
+func NewConfigSource(client *etcdClientV3.Client, prefix string, cacheFile string) *ConfigSource {
+	ctx, cancel := context.WithCancel(context.Background())
+	source := &ConfigSource{
+		client:    client,
+		prefixes:  configPrefixes(prefix),
+		cacheFile: cacheFile,
+		cancel:    cancel,
+		valueMaps: map[string]map[string]any{},
+	}
+
+	cacheMaps := source.readCache()
+	for _, prefix := range source.prefixes {
+		revision, err := source.load(ctx, prefix)
+		if err != nil {
+			logger.Warn("读取etcd配置[%s]失败，使用本地缓存：%v", prefix, err)
+			source.setValues(prefix, cacheMaps[prefix], false)
+		}
+		source.watchers.Add(1)
+		go source.watch(ctx, prefix, revision)
+	}
+	return source
+}
+
+// GetConfigSource 启动时候按照配置（gole.etcd.config.enable）加载的配置源，没有加载则为nil
+func GetConfigSource() *ConfigSource {
+	return defaultConfigSource
+}
+
+// CloseConfigSource 关闭启动时候按照配置加载的配置源
+func CloseConfigSource() {
+	if defaultConfigSource != nil {
+		defaultConfigSource.Close()
+	}
+}
+
+// Close 停止监听并等待监听的协程退出，已经加载的配置保留
+func (source *ConfigSource) Close() {
+	source.cancel()
+	source.watchers.Wait()
+}
+
+// 读取前缀下所有的配置，返回读取时候的revision
+func (source *ConfigSource) load(ctx context.Context, prefix string) (int64, error) {
+	readCtx, cancel := context.WithTimeout(ctx, configReadTimeout)
+	defer cancel()
+	rsp, err := source.client.Get(readCtx, prefix, etcdClientV3.WithPrefix())
+	if err != nil {
+		return 0, err
+	}
+
+	valueMap := map[string]any{}
+	for _, kv := range rsp.Kvs {
+		if key := configKey(prefix, string(kv.Key)); key != "" {
+			valueMap[key] = string(kv.Value)
+		}
+	}
+	source.setValues(prefix, valueMap, true)
+	return rsp.Header.Revision, nil
+}
+
+// 监听前缀下key的变更；监听中断（比如：etcd不可用、revision被压缩）后重新读取全部配置再继续监听
+func (source *ConfigSource) watch(ctx context.Context, prefix string, revision int64) {
+	defer source.watchers.Done()
+	for ctx.Err() == nil {
+		if revision == 0 {
+			var err error
+			if revision, err = source.load(ctx, prefix); err != nil {
+				logger.Warn("读取etcd配置[%s]失败，%v后重试：%v", prefix, configRetryInterval, err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(configRetryInterval):
+				}
+				continue
+			}
+		}
+
+		watchCtx, cancel := context.WithCancel(etcdClientV3.WithRequireLeader(ctx))
+		for rsp := range source.client.Watch(watchCtx, prefix, etcdClientV3.WithPrefix(), etcdClientV3.WithRev(revision+1)) {
+			if rsp.Err() != nil {
+				logger.Warn("监听etcd配置[%s]异常：%v", prefix, rsp.Err())
+				break
+			}
+			source.applyEvents(prefix, rsp.Events)
+			revision = rsp.Header.Revision
+		}
+		cancel()
+		// 重新读取全部配置，避免遗漏中断期间的变更
+		revision = 0
+	}
+}
+
+func (source *ConfigSource) applyEvents(prefix string, events []*etcdClientV3.Event) {
+	source.lock.Lock()
+	valueMap := map[string]any{}
+	for key, value := range source.valueMaps[prefix] {
+		valueMap[key] = value
+	}
+	source.lock.Unlock()
+
+	for _, event := range events {
+		key := configKey(prefix, string(event.Kv.Key))
+		if key == "" {
+			continue
+		}
+		if event.Type == etcdClientV3.EventTypeDelete {
+			delete(valueMap, key)
+		} else {
+			valueMap[key] = string(event.Kv.Value)
+		}
+	}
+	source.setValues(prefix, valueMap, true)
+}
+
+// 更新配置源（发布配置变更事件），writeCache表示是否写入本地缓存
+func (source *ConfigSource) setValues(prefix string, valueMap map[string]any, writeCache bool) {
+	if valueMap == nil {
+		valueMap = map[string]any{}
+	}
+	source.lock.Lock()
+	source.valueMaps[prefix] = valueMap
+	source.lock.Unlock()
+
+	config.SetPropertySource("etcd:"+prefix, config.SourceTypeRemote, valueMap)
+	if writeCache {
+		source.writeCache()
+	}
+}
+
+func (source *ConfigSource) readCache() map[string]map[string]any {
+	cacheMaps := map[string]map[string]any{}
+	content, err := os.ReadFile(source.cacheFile)
+	if err != nil {
+		return cacheMaps
+	}
+	if err := json.Unmarshal(content, &cacheMaps); err != nil {
+		logger.Warn("读取etcd配置的本地缓存[%s]失败：%v", source.cacheFile, err)
+	}
+	return cacheMaps
+}
+
+func (source *ConfigSource) writeCache() {
+	source.cacheLock.Lock()
+	defer source.cacheLock.Unlock()
+
+	source.lock.Lock()
+	content, err := json.MarshalIndent(source.valueMaps, "", "  ")
+	source.lock.Unlock()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(source.cacheFile), 0755); err != nil {
+		logger.Warn("写入etcd配置的本地缓存[%s]失败：%v", source.cacheFile, err)
+		return
+	}
+	if err := os.WriteFile(source.cacheFile, content, 0600); err != nil {
+		logger.Warn("写入etcd配置的本地缓存[%s]失败：%v", source.cacheFile, err)
+	}
+}
+
+// 替换前缀中的{app}和{profile}，多个profile时候按照profile的顺序返回多个前缀（后面的优先级高）
+func configPrefixes(prefix string) []string {
+	prefix = strings.ReplaceAll(prefix, "{app}", config.GetValueString("gole.application.name"))
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if !strings.Contains(prefix, "{profile}") {
+		return []string{prefix}
+	}
+
+	profiles := config.GetActiveProfiles()
+	if len(profiles) == 0 {
+		profiles = []string{"default"}
+	}
+	var prefixes []string
+	for _, profile := range profiles {
+		prefixes = append(prefixes, strings.ReplaceAll(prefix, "{profile}", profile))
+	}
+	return prefixes
+}
+
+// etcd的key转换为配置的key：去掉前缀，"/"转换为"."
+func configKey(prefix string, etcdKey string) string {
+	key := strings.Trim(strings.TrimPrefix(etcdKey, prefix), "/")
+	return strings.ReplaceAll(key, "/", ".")
+}
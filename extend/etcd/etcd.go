@@ -32,6 +32,15 @@ func init() {
 
 	EtcdHooks = []GoleEtcdHook{}
 	//grpclog.SetLoggerV2(&EtcdLogger{})
+
+	// etcd作为配置源
+	if config.ExistConfigFile() && config.GetValueBoolDefault("gole.etcd.config.enable", false) {
+		source, err := LoadConfigSource()
+		if err != nil {
+			logger.Warn("加载etcd配置源异常：%v", err)
+		}
+		defaultConfigSource = source
+	}
 }
 
 func NewEtcdClient() (*EtcdClientWrap, error) {
@@ -0,0 +1,99 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/config"
+	"github.com/simonalong/gole/extend/etcd"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// 启动内嵌的etcd
+func startEmbedEtcd(t *testing.T) (*embed.Etcd, string) {
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	clientUrl, _ := url.Parse(fmt.Sprintf("http://127.0.0.1:%d", freePort(t)))
+	peerUrl, _ := url.Parse(fmt.Sprintf("http://127.0.0.1:%d", freePort(t)))
+	cfg.LCUrls, cfg.ACUrls = []url.URL{*clientUrl}, []url.URL{*clientUrl}
+	cfg.LPUrls, cfg.APUrls = []url.URL{*peerUrl}, []url.URL{*peerUrl}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		server.Close()
+		t.Fatal("启动内嵌etcd超时")
+	}
+	return server, clientUrl.String()
+}
+
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func waitValue(key, expect string) string {
+	for i := 0; i < 100; i++ {
+		if value := config.GetValueString(key); value == expect {
+			return value
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return config.GetValueString(key)
+}
+
+func TestConfigSource(t *testing.T) {
+	config.SetValue("gole.application.name", "demo")
+	server, endpoint := startEmbedEtcd(t)
+
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{endpoint}, DialTimeout: 3 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	client.Put(ctx, "/config/demo/default/gole.etcd-test.name", "remote")
+	client.Put(ctx, "/config/demo/default/gole/etcd-test/port", "8080")
+
+	cacheFile := filepath.Join(t.TempDir(), "cache.json")
+	source := etcd.NewConfigSource(client, "/config/{app}/{profile}/", cacheFile)
+	defer source.Close()
+
+	assert.Equal(t, config.GetValueString("gole.etcd-test.name"), "remote")
+	assert.Equal(t, config.GetValueInt("gole.etcd-test.port"), 8080)
+	assert.Equal(t, config.GetValueOrigin("gole.etcd-test.name").Source, "etcd:/config/demo/default/")
+
+	// 远端修改和删除
+	client.Put(ctx, "/config/demo/default/gole.etcd-test.name", "changed")
+	assert.Equal(t, waitValue("gole.etcd-test.name", "changed"), "changed")
+	client.Delete(ctx, "/config/demo/default/gole/etcd-test/port")
+	assert.Equal(t, waitValue("gole.etcd-test.port", ""), "")
+	source.Close()
+
+	// etcd不可用的时候使用本地缓存
+	config.RemovePropertySource("etcd:/config/demo/default/")
+	server.Close()
+	offlineClient, _ := clientv3.New(clientv3.Config{Endpoints: []string{endpoint}, DialTimeout: time.Second})
+	defer offlineClient.Close()
+	offlineSource := etcd.NewConfigSource(offlineClient, "/config/{app}/{profile}/", cacheFile)
+	defer offlineSource.Close()
+	assert.Equal(t, config.GetValueString("gole.etcd-test.name"), "changed")
+}
@@ -0,0 +1,55 @@
+package kafka
+
+import "github.com/simonalong/gole/config"
+
+func init() {
+	config.RegisterKeyMetadata(
+		config.KeyMetadata{Key: "gole.kafka.enable", Type: "bool", Default: "false", Description: "是否启用kafka"},
+		config.KeyMetadata{Key: "gole.kafka.addrs", Type: "list", Description: "kafka的节点地址"},
+		config.KeyMetadata{Key: "gole.kafka.client-id", Type: "string", Default: "sarama", Description: "客户端id"},
+		config.KeyMetadata{Key: "gole.kafka.channel-buffer-size", Type: "int", Default: "256"},
+		config.KeyMetadata{Key: "gole.kafka.api-versions-request", Type: "bool", Default: "true"},
+		config.KeyMetadata{Key: "gole.kafka.version", Type: "string", Default: "V1_0_0_0", Description: "kafka的版本，格式为V{x}_{x}_{x}_{x}"},
+		config.KeyMetadata{Key: "gole.kafka.admin.retry-max", Type: "int", Default: "5"},
+		config.KeyMetadata{Key: "gole.kafka.admin.retry-backoff", Type: "duration", Default: "100ms"},
+		config.KeyMetadata{Key: "gole.kafka.admin.timeout", Type: "duration", Default: "3s"},
+		config.KeyMetadata{Key: "gole.kafka.net.max-open-requests", Type: "int", Default: "5"},
+		config.KeyMetadata{Key: "gole.kafka.net.dial-timeout", Type: "duration", Default: "3s"},
+		config.KeyMetadata{Key: "gole.kafka.net.read-timeout", Type: "duration", Default: "3s"},
+		config.KeyMetadata{Key: "gole.kafka.net.write-timeout", Type: "duration", Default: "3s"},
+		config.KeyMetadata{Key: "gole.kafka.net.SASL-handshake", Type: "bool", Default: "true"},
+		config.KeyMetadata{Key: "gole.kafka.net.SASL-version", Type: "int", Default: "0", Description: "只可以为0和1"},
+		config.KeyMetadata{Key: "gole.kafka.metadata.retry-max", Type: "int", Default: "3"},
+		config.KeyMetadata{Key: "gole.kafka.metadata.retry-backoff", Type: "duration", Default: "250ms"},
+		config.KeyMetadata{Key: "gole.kafka.metadata.refresh-frequency", Type: "duration", Default: "10m"},
+		config.KeyMetadata{Key: "gole.kafka.metadata.full", Type: "bool", Default: "true"},
+		config.KeyMetadata{Key: "gole.kafka.metadata.allow-auto-topic-creation", Type: "bool", Default: "true"},
+		config.KeyMetadata{Key: "gole.kafka.producer.max-message-bytes", Type: "int", Default: "1000000"},
+		config.KeyMetadata{Key: "gole.kafka.producer.required-acks", Type: "int", Default: "1", Description: "只可以为：-1、0、1"},
+		config.KeyMetadata{Key: "gole.kafka.producer.timeout", Type: "duration", Default: "10s"},
+		config.KeyMetadata{Key: "gole.kafka.producer.retry-max", Type: "int", Default: "3"},
+		config.KeyMetadata{Key: "gole.kafka.producer.retry-backoff", Type: "duration", Default: "100ms"},
+		config.KeyMetadata{Key: "gole.kafka.producer.return-errors", Type: "bool", Default: "true"},
+		config.KeyMetadata{Key: "gole.kafka.producer.return-success", Type: "bool", Default: "false"},
+		config.KeyMetadata{Key: "gole.kafka.producer.compression-level", Type: "int", Default: "-1000"},
+		config.KeyMetadata{Key: "gole.kafka.producer.transaction-timeout", Type: "duration", Default: "1m"},
+		config.KeyMetadata{Key: "gole.kafka.producer.transaction-retry-max", Type: "int", Default: "50"},
+		config.KeyMetadata{Key: "gole.kafka.producer.transaction-retry-backoff", Type: "duration", Default: "100ms"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.fetch-min", Type: "int", Default: "1"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.fetch-default", Type: "int", Default: "1048576"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.retry-backoff", Type: "duration", Default: "2s"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.max-wait-time", Type: "duration", Default: "500ms"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.max-processing-time", Type: "duration", Default: "100ms"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.return-errors", Type: "bool", Default: "false"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.offsets-auto-commit-enable", Type: "bool", Default: "false"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.offsets-auto-commit-interval", Type: "duration", Default: "1s"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.offsets-initial", Type: "int", Default: "-1"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.offsets-retry-max", Type: "int", Default: "3"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.group.session-timeout", Type: "duration", Default: "10s"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.group.heartbeat-interval", Type: "duration", Default: "3s"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.group.rebalance-timeout", Type: "duration", Default: "60s"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.group.rebalance-retry-max", Type: "int", Default: "4"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.group.rebalance-retry-backoff", Type: "duration", Default: "2s"},
+		config.KeyMetadata{Key: "gole.kafka.consumer.group.reset-invalid-offsets", Type: "bool", Default: "true"},
+	)
+}
@@ -0,0 +1,39 @@
+package redis
+
+import "github.com/simonalong/gole/config"
+
+func init() {
+	config.RegisterKeyMetadata(
+		config.KeyMetadata{Key: "gole.redis.enable", Type: "bool", Default: "false", Description: "是否启用redis"},
+		config.KeyMetadata{Key: "gole.redis.username", Type: "string", Description: "用户"},
+		config.KeyMetadata{Key: "gole.redis.password", Type: "string", Description: "密码"},
+		config.KeyMetadata{Key: "gole.redis.standalone.addr", Type: "string", Description: "单节点模式：节点地址"},
+		config.KeyMetadata{Key: "gole.redis.standalone.database", Type: "int", Default: "0", Description: "单节点模式：数据库"},
+		config.KeyMetadata{Key: "gole.redis.standalone.network", Type: "string", Default: "tcp", Description: "单节点模式：网络类型，tcp或者unix"},
+		config.KeyMetadata{Key: "gole.redis.standalone.read-only", Type: "bool", Default: "false", Description: "单节点模式：开启从节点的只读功能"},
+		config.KeyMetadata{Key: "gole.redis.sentinel.master", Type: "string", Description: "哨兵模式：哨兵的集群名字"},
+		config.KeyMetadata{Key: "gole.redis.sentinel.addrs", Type: "list", Description: "哨兵模式：哨兵节点地址"},
+		config.KeyMetadata{Key: "gole.redis.sentinel.database", Type: "int", Default: "0", Description: "哨兵模式：数据库"},
+		config.KeyMetadata{Key: "gole.redis.sentinel.sentinel-user", Type: "string", Description: "哨兵模式：哨兵用户"},
+		config.KeyMetadata{Key: "gole.redis.sentinel.sentinel-password", Type: "string", Description: "哨兵模式：哨兵密码"},
+		config.KeyMetadata{Key: "gole.redis.sentinel.slave-only", Type: "bool", Default: "false", Description: "哨兵模式：将所有命令路由到从属只读节点"},
+		config.KeyMetadata{Key: "gole.redis.cluster.addrs", Type: "list", Description: "集群模式：节点地址"},
+		config.KeyMetadata{Key: "gole.redis.cluster.max-redirects", Type: "int", Default: "3", Description: "集群模式：最大重定向次数"},
+		config.KeyMetadata{Key: "gole.redis.cluster.read-only", Type: "bool", Default: "false", Description: "集群模式：开启从节点的只读功能"},
+		config.KeyMetadata{Key: "gole.redis.cluster.route-by-latency", Type: "bool", Default: "false", Description: "集群模式：允许将只读命令路由到最近的主节点或从节点"},
+		config.KeyMetadata{Key: "gole.redis.cluster.route-randomly", Type: "bool", Default: "false", Description: "集群模式：允许将只读命令路由到随机的主节点或从节点"},
+		config.KeyMetadata{Key: "gole.redis.max-retries", Type: "int", Default: "3", Description: "命令执行失败时候的最大重试次数，-1则不重试"},
+		config.KeyMetadata{Key: "gole.redis.min-retry-backoff", Type: "int", Default: "8", Description: "（单位毫秒）每次重试的最小回退时间，-1则禁止回退"},
+		config.KeyMetadata{Key: "gole.redis.max-retry-backoff", Type: "int", Default: "512", Description: "（单位毫秒）每次重试的最大回退时间，-1则禁止回退"},
+		config.KeyMetadata{Key: "gole.redis.dial-timeout", Type: "int", Default: "15000", Description: "（单位毫秒）创建新链接的拨号超时时间"},
+		config.KeyMetadata{Key: "gole.redis.read-timeout", Type: "int", Default: "3000", Description: "（单位毫秒）读超时，-1表示无超时"},
+		config.KeyMetadata{Key: "gole.redis.write-timeout", Type: "int", Default: "3000", Description: "（单位毫秒）写超时，-1表示无超时"},
+		config.KeyMetadata{Key: "gole.redis.pool-fifo", Type: "bool", Default: "false", Description: "连接池类型：fifo：true；lifo：false"},
+		config.KeyMetadata{Key: "gole.redis.pool-size", Type: "int", Description: "最大连接池大小，默认每个cpu核10个连接"},
+		config.KeyMetadata{Key: "gole.redis.min-idle-conns", Type: "int", Description: "最小空闲连接数"},
+		config.KeyMetadata{Key: "gole.redis.max-conn-age", Type: "int", Description: "（单位毫秒）连接存活时长，默认不关闭"},
+		config.KeyMetadata{Key: "gole.redis.pool-timeout", Type: "int", Description: "（单位毫秒）链接池中的链接都在忙时候的等待时间，默认读超时+1秒"},
+		config.KeyMetadata{Key: "gole.redis.idle-timeout", Type: "int", Default: "300000", Description: "（单位毫秒）空闲链接时间，-1表示禁用超时检查"},
+		config.KeyMetadata{Key: "gole.redis.idle-check-frequency", Type: "int", Default: "60000", Description: "（单位毫秒）空闲链接核查频率，-1禁止空闲链接核查"},
+	)
+}
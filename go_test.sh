@@ -1,6 +1,6 @@
 #!/bin/bash
 
-go test ./config/test
+go test -race ./config/test
 go test ./util/test
 go test ./validate/test
 go test ./compress/test
@@ -0,0 +1,168 @@
+package json
+
+import (
+	"fmt"
+
+	"github.com/simonalong/gole/util"
+)
+
+// Array 顶层为数组的json，比如：[{"name":"a"},{"name":"b"}]
+type Array struct {
+	Values []any
+}
+
+func (jsonArray *Array) Load(jsonContent string) error {
+	data, err := decodeJson(jsonContent)
+	if err != nil {
+		return err
+	}
+	values, ok := data.([]any)
+	if !ok {
+		return fmt.Errorf("json is not an array: %v", jsonContent)
+	}
+	jsonArray.Values = values
+	return nil
+}
+
+func (jsonArray *Array) IsEmpty() bool {
+	return len(jsonArray.Values) == 0
+}
+
+func (jsonArray *Array) Len() int {
+	return len(jsonArray.Values)
+}
+
+// Get 下标对应的元素，负数为从后往前；越界返回nil
+func (jsonArray *Array) Get(index int) any {
+	if index, exist := listIndex(jsonArray.Values, index); exist {
+		return jsonArray.Values[index]
+	}
+	return nil
+}
+
+func (jsonArray *Array) Add(value any) {
+	jsonArray.Values = append(jsonArray.Values, toJsonData(value))
+}
+
+// GetObject 下标对应的元素为对象时，转换为Object
+func (jsonArray *Array) GetObject(index int) *Object {
+	dataMap, ok := jsonArray.Get(index).(map[string]any)
+	if !ok {
+		return nil
+	}
+	jsonObject := &Object{ValueDeepMap: dataMap}
+	jsonObject.refreshValueMap()
+	return jsonObject
+}
+
+// GetPath 按照路径获取值，路径以下标开头，比如：[0].name、[*].price、[?(@.price > 10)].name，规则同Object的GetPath
+func (jsonArray *Array) GetPath(path string) any {
+	return getPathValue(jsonArray.Values, path)
+}
+
+// SetPath 按照路径设置值，路径以下标开头，规则同Object的SetPath
+func (jsonArray *Array) SetPath(path string, value any) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("path %q: can not set the root", path)
+	}
+	if segments[0].kind == segmentKey {
+		return fmt.Errorf("path %q: json array must start with index", path)
+	}
+	values := jsonArray.Values
+	if values == nil {
+		values = []any{}
+	}
+	node, err := setPath(values, segments, toJsonData(value))
+	if err != nil {
+		return err
+	}
+	jsonArray.Values = node.([]any)
+	return nil
+}
+
+// DeletePath 按照路径删除，返回删除的个数
+func (jsonArray *Array) DeletePath(path string) int {
+	segments, err := parsePath(path)
+	if err != nil || len(segments) == 0 || jsonArray.Values == nil {
+		return 0
+	}
+	node, count := deletePath(jsonArray.Values, segments)
+	jsonArray.Values = node.([]any)
+	return count
+}
+
+// ToJson 转换为json字符串
+func (jsonArray *Array) ToJson() string {
+	if jsonArray.Values == nil {
+		return "[]"
+	}
+	return toJsonString(jsonArray.Values)
+}
+
+func (jsonArray *Array) GetPathString(path string) string {
+	return util.ToString(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathInt(path string) int {
+	return util.ToInt(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathInt8(path string) int8 {
+	return util.ToInt8(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathInt16(path string) int16 {
+	return util.ToInt16(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathInt32(path string) int32 {
+	return util.ToInt32(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathInt64(path string) int64 {
+	return util.ToInt64(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathUInt(path string) uint {
+	return util.ToUInt(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathUInt8(path string) uint8 {
+	return util.ToUInt8(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathUInt16(path string) uint16 {
+	return util.ToUInt16(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathUInt32(path string) uint32 {
+	return util.ToUInt32(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathUInt64(path string) uint64 {
+	return util.ToUInt64(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathFloat32(path string) float32 {
+	return util.ToFloat32(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathFloat64(path string) float64 {
+	return util.ToFloat64(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathBool(path string) bool {
+	return util.ToBool(firstPathValue(jsonArray.GetPath(path), path))
+}
+
+func (jsonArray *Array) GetPathObject(path string, targetPtrObj any) error {
+	return util.DataToObject(jsonArray.GetPath(path), targetPtrObj)
+}
+
+func (jsonArray *Array) GetPathArray(path string) []any {
+	return toArray(jsonArray.GetPath(path))
+}
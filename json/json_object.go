@@ -264,3 +264,126 @@ func (jsonObject *Object) GetArray(key string) []any {
 	}
 	return arrayResult
 }
+
+// GetPath 按照JSONPath风格的路径获取值，比如：data.items[2].price、$.data.items[-1]、data.items[*].price、data.items[?(@.price > 10)].name；
+// 确定的路径返回对应的值，包含通配符或者过滤的路径返回所有匹配的值（[]any）；路径不存在或者不合法时返回nil
+func (jsonObject *Object) GetPath(path string) any {
+	return getPathValue(jsonObject.ValueDeepMap, path)
+}
+
+// SetPath 按照路径设置值，路径中不存在的对象和数组会自动创建（数组的下标最大为数组的长度，即追加到最后）；通配符以及过滤只设置匹配到的节点
+func (jsonObject *Object) SetPath(path string, value any) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("path %q: can not set the root", path)
+	}
+	if segments[0].kind == segmentIndex {
+		return fmt.Errorf("path %q: json object can not start with index", path)
+	}
+	if jsonObject.ValueDeepMap == nil {
+		jsonObject.ValueDeepMap = make(map[string]any)
+	}
+	if _, err = setPath(jsonObject.ValueDeepMap, segments, toJsonData(value)); err != nil {
+		return err
+	}
+	jsonObject.refreshValueMap()
+	return nil
+}
+
+// DeletePath 按照路径删除，返回删除的个数；数组中的元素删除后，后面的元素前移
+func (jsonObject *Object) DeletePath(path string) int {
+	segments, err := parsePath(path)
+	if err != nil || len(segments) == 0 || jsonObject.ValueDeepMap == nil {
+		return 0
+	}
+	_, count := deletePath(jsonObject.ValueDeepMap, segments)
+	if count > 0 {
+		jsonObject.refreshValueMap()
+	}
+	return count
+}
+
+// 修改ValueDeepMap之后同步平铺的ValueMap
+func (jsonObject *Object) refreshValueMap() {
+	property, _ := util.MapToProperties(jsonObject.ValueDeepMap)
+	valueMap, _ := util.PropertiesToMap(property)
+	if valueMap == nil {
+		valueMap = make(map[string]any)
+	}
+	jsonObject.ValueMap = valueMap
+}
+
+// ToJson 转换为json字符串，key按照字典序排列
+func (jsonObject *Object) ToJson() string {
+	if jsonObject.ValueDeepMap == nil {
+		return "{}"
+	}
+	return toJsonString(jsonObject.ValueDeepMap)
+}
+
+func (jsonObject *Object) GetPathString(path string) string {
+	return util.ToString(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathInt(path string) int {
+	return util.ToInt(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathInt8(path string) int8 {
+	return util.ToInt8(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathInt16(path string) int16 {
+	return util.ToInt16(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathInt32(path string) int32 {
+	return util.ToInt32(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathInt64(path string) int64 {
+	return util.ToInt64(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathUInt(path string) uint {
+	return util.ToUInt(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathUInt8(path string) uint8 {
+	return util.ToUInt8(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathUInt16(path string) uint16 {
+	return util.ToUInt16(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathUInt32(path string) uint32 {
+	return util.ToUInt32(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathUInt64(path string) uint64 {
+	return util.ToUInt64(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathFloat32(path string) float32 {
+	return util.ToFloat32(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathFloat64(path string) float64 {
+	return util.ToFloat64(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathBool(path string) bool {
+	return util.ToBool(firstPathValue(jsonObject.GetPath(path), path))
+}
+
+func (jsonObject *Object) GetPathObject(path string, targetPtrObj any) error {
+	return util.DataToObject(jsonObject.GetPath(path), targetPtrObj)
+}
+
+func (jsonObject *Object) GetPathArray(path string) []any {
+	return toArray(jsonObject.GetPath(path))
+}
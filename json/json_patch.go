@@ -0,0 +1,675 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/simonalong/gole/util"
+)
+
+/**
+ * json的局部修改：
+ *  1.merge patch（RFC 7386）：patch为一个json，对象中的key合并到原文档中，值为null的删除，其他的值（包括数组）直接替换
+ *  2.json patch（RFC 6902）：patch为操作的数组，支持add、remove、replace、move、copy、test，路径为json pointer（RFC 6901），比如：/data/items/0/name，其中"~"转义为"~0"，"/"转义为"~1"，数组的"-"表示末尾
+ *  3.通过对比两个文档生成patch：CreateMergePatch、CreatePatch
+ */
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Operation json patch中的一个操作
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Patch json patch（RFC 6902），按照顺序执行，其中任意一个失败则整体不生效
+type Patch []Operation
+
+// PatchError 执行json patch的错误，Index为出错的操作的下标
+type PatchError struct {
+	Index     int
+	Operation Operation
+	Msg       string
+}
+
+func (patchError *PatchError) Error() string {
+	return fmt.Sprintf("json patch operation %d (%s %s) failed: %s", patchError.Index, patchError.Operation.Op, patchError.Operation.Path, patchError.Msg)
+}
+
+// MarshalJSON value为null的时候也要输出（add、replace、test需要value）
+func (operation Operation) MarshalJSON() ([]byte, error) {
+	data := map[string]any{"op": operation.Op, "path": operation.Path}
+	if operation.From != "" || operation.Op == OpMove || operation.Op == OpCopy {
+		data["from"] = operation.From
+	}
+	if operation.Op == OpAdd || operation.Op == OpReplace || operation.Op == OpTest {
+		data["value"] = operation.Value
+	}
+	return marshalJson(data)
+}
+
+// DecodePatch 解析json patch，比如：[{"op": "replace", "path": "/name", "value": "simon"}]
+func DecodePatch(patchContent []byte) (Patch, error) {
+	data, err := decodeJson(string(patchContent))
+	if err != nil {
+		return nil, err
+	}
+	items, ok := data.([]any)
+	if !ok {
+		return nil, fmt.Errorf("json patch must be an array of operations")
+	}
+
+	patch := make(Patch, 0, len(items))
+	for index, item := range items {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("json patch operation %d is not an object", index)
+		}
+		operation := Operation{Op: util.ToString(itemMap["op"]), Path: util.ToString(itemMap["path"]), From: util.ToString(itemMap["from"]), Value: itemMap["value"]}
+		if _, exist := itemMap["path"]; !exist {
+			return nil, fmt.Errorf("json patch operation %d: path is missing", index)
+		}
+		switch operation.Op {
+		case OpAdd, OpReplace, OpTest:
+			if _, exist := itemMap["value"]; !exist {
+				return nil, fmt.Errorf("json patch operation %d: value is missing", index)
+			}
+		case OpMove, OpCopy:
+			if _, exist := itemMap["from"]; !exist {
+				return nil, fmt.Errorf("json patch operation %d: from is missing", index)
+			}
+		case OpRemove:
+		default:
+			return nil, fmt.Errorf("json patch operation %d: op %q is not supported", index, operation.Op)
+		}
+		patch = append(patch, operation)
+	}
+	return patch, nil
+}
+
+// ToJson 转换为json字符串
+func (patch Patch) ToJson() string {
+	if patch == nil {
+		return "[]"
+	}
+	content, err := marshalJson([]Operation(patch))
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// Apply 在json文档上执行patch，返回新的文档
+func (patch Patch) Apply(document []byte) ([]byte, error) {
+	data, err := decodeJson(string(document))
+	if err != nil {
+		return nil, err
+	}
+	result, err := patch.applyData(data)
+	if err != nil {
+		return nil, err
+	}
+	return marshalJson(result)
+}
+
+// 在数据的副本上执行，任意一个操作失败都不会修改原数据
+func (patch Patch) applyData(data any) (any, error) {
+	document := copyJsonData(data)
+	var err error
+	for index, operation := range patch {
+		if document, err = applyOperation(document, operation); err != nil {
+			return nil, &PatchError{Index: index, Operation: operation, Msg: err.Error()}
+		}
+	}
+	return document, nil
+}
+
+func applyOperation(document any, operation Operation) (any, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch operation.Op {
+	case OpAdd:
+		return pointerAdd(document, path, copyJsonData(toJsonData(operation.Value)))
+	case OpRemove:
+		document, _, err = pointerRemove(document, path)
+		return document, err
+	case OpReplace:
+		if _, err = pointerGet(document, path); err != nil {
+			return nil, err
+		}
+		if document, _, err = pointerRemove(document, path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, copyJsonData(toJsonData(operation.Value)))
+	case OpMove:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > len(from) && isPointerPrefix(from, path) {
+			return nil, fmt.Errorf("can not move %q into its child %q", operation.From, operation.Path)
+		}
+		document, value, err := pointerRemove(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, value)
+	case OpCopy:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := pointerGet(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, copyJsonData(value))
+	case OpTest:
+		value, err := pointerGet(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(value, toJsonData(operation.Value)) {
+			return nil, fmt.Errorf("value is %v, not %v", toJsonString(value), toJsonString(toJsonData(operation.Value)))
+		}
+		return document, nil
+	}
+	return nil, fmt.Errorf("op %q is not supported", operation.Op)
+}
+
+// 解析json pointer：""为整个文档，其他的以"/"开头
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("json pointer %q must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for index, token := range tokens {
+		tokens[index] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func formatPointer(tokens []string) string {
+	var builder strings.Builder
+	for _, token := range tokens {
+		builder.WriteByte('/')
+		builder.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return builder.String()
+}
+
+func isPointerPrefix(prefix, tokens []string) bool {
+	for index := range prefix {
+		if prefix[index] != tokens[index] {
+			return false
+		}
+	}
+	return true
+}
+
+// 数组的下标：不能有前导0，不能为负数
+func pointerIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, fmt.Errorf("array index %q is illegal", token)
+	}
+	maxIndex := length - 1
+	if allowEnd {
+		maxIndex = length
+	}
+	if index > maxIndex {
+		return 0, fmt.Errorf("array index %d out of range %d", index, length)
+	}
+	return index, nil
+}
+
+func pointerGet(document any, tokens []string) (any, error) {
+	node := document
+	for position, token := range tokens {
+		switch data := node.(type) {
+		case map[string]any:
+			value, exist := data[token]
+			if !exist {
+				return nil, fmt.Errorf("path %q does not exist", formatPointer(tokens[:position+1]))
+			}
+			node = value
+		case []any:
+			index, err := pointerIndex(token, len(data), false)
+			if err != nil {
+				return nil, err
+			}
+			node = data[index]
+		default:
+			return nil, fmt.Errorf("path %q does not exist", formatPointer(tokens[:position+1]))
+		}
+	}
+	return node, nil
+}
+
+// 添加：对象中的key已存在则替换，数组中的插入到对应的位置；父节点必须存在
+func pointerAdd(document any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parentTokens, last := tokens[:len(tokens)-1], tokens[len(tokens)-1]
+	parent, err := pointerGet(document, parentTokens)
+	if err != nil {
+		return nil, err
+	}
+	switch data := parent.(type) {
+	case map[string]any:
+		data[last] = value
+		return document, nil
+	case []any:
+		index, err := pointerIndex(last, len(data), true)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, 0, len(data)+1)
+		list = append(append(append(list, data[:index]...), value), data[index:]...)
+		return pointerReplaceParent(document, parentTokens, list)
+	}
+	return nil, fmt.Errorf("path %q is not an object or an array", formatPointer(parentTokens))
+}
+
+// 删除，返回删除之后的文档以及删除的值
+func pointerRemove(document any, tokens []string) (any, any, error) {
+	if len(tokens) == 0 {
+		return nil, document, nil
+	}
+	parentTokens, last := tokens[:len(tokens)-1], tokens[len(tokens)-1]
+	parent, err := pointerGet(document, parentTokens)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch data := parent.(type) {
+	case map[string]any:
+		value, exist := data[last]
+		if !exist {
+			return nil, nil, fmt.Errorf("path %q does not exist", formatPointer(tokens))
+		}
+		delete(data, last)
+		return document, value, nil
+	case []any:
+		index, err := pointerIndex(last, len(data), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := data[index]
+		list := make([]any, 0, len(data)-1)
+		list = append(append(list, data[:index]...), data[index+1:]...)
+		document, err = pointerReplaceParent(document, parentTokens, list)
+		return document, value, err
+	}
+	return nil, nil, fmt.Errorf("path %q does not exist", formatPointer(tokens))
+}
+
+// 数组长度变化之后替换父节点中的数组
+func pointerReplaceParent(document any, tokens []string, list []any) (any, error) {
+	if len(tokens) == 0 {
+		return list, nil
+	}
+	grandParent, err := pointerGet(document, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch data := grandParent.(type) {
+	case map[string]any:
+		data[last] = list
+	case []any:
+		index, _ := pointerIndex(last, len(data), false)
+		data[index] = list
+	}
+	return document, nil
+}
+
+// CreatePatch 对比两个json文档生成json patch：对象逐个key对比，长度相同的数组逐个元素对比，其他的整体替换
+func CreatePatch(original, modified []byte) (Patch, error) {
+	originalData, err := decodeJson(string(original))
+	if err != nil {
+		return nil, err
+	}
+	modifiedData, err := decodeJson(string(modified))
+	if err != nil {
+		return nil, err
+	}
+	return diffData(originalData, modifiedData), nil
+}
+
+func diffData(original, modified any) Patch {
+	patch := Patch{}
+	diffNode(&patch, []string{}, original, modified)
+	return patch
+}
+
+func diffNode(patch *Patch, tokens []string, original, modified any) {
+	if jsonEqual(original, modified) {
+		return
+	}
+	originalMap, originalOk := original.(map[string]any)
+	modifiedMap, modifiedOk := modified.(map[string]any)
+	if originalOk && modifiedOk {
+		for _, key := range sortedKeys(originalMap) {
+			if _, exist := modifiedMap[key]; !exist {
+				*patch = append(*patch, Operation{Op: OpRemove, Path: formatPointer(appendToken(tokens, key))})
+			}
+		}
+		for _, key := range sortedKeys(modifiedMap) {
+			originalValue, exist := originalMap[key]
+			if !exist {
+				*patch = append(*patch, Operation{Op: OpAdd, Path: formatPointer(appendToken(tokens, key)), Value: copyJsonData(modifiedMap[key])})
+				continue
+			}
+			diffNode(patch, appendToken(tokens, key), originalValue, modifiedMap[key])
+		}
+		return
+	}
+
+	originalList, originalOk := original.([]any)
+	modifiedList, modifiedOk := modified.([]any)
+	if originalOk && modifiedOk && len(originalList) == len(modifiedList) {
+		for index := range originalList {
+			diffNode(patch, appendToken(tokens, strconv.Itoa(index)), originalList[index], modifiedList[index])
+		}
+		return
+	}
+	*patch = append(*patch, Operation{Op: OpReplace, Path: formatPointer(tokens), Value: copyJsonData(modified)})
+}
+
+func appendToken(tokens []string, token string) []string {
+	result := make([]string, 0, len(tokens)+1)
+	return append(append(result, tokens...), token)
+}
+
+// MergePatch 在json文档上执行merge patch（RFC 7386），返回新的文档
+func MergePatch(document, patch []byte) ([]byte, error) {
+	documentData, err := decodeJson(string(document))
+	if err != nil {
+		return nil, err
+	}
+	patchData, err := decodeJson(string(patch))
+	if err != nil {
+		return nil, err
+	}
+	return marshalJson(mergePatchData(documentData, patchData))
+}
+
+func mergePatchData(target, patch any) any {
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return copyJsonData(patch)
+	}
+	targetMap, ok := target.(map[string]any)
+	if !ok {
+		targetMap = map[string]any{}
+	}
+	for key, value := range patchMap {
+		if nil == value {
+			delete(targetMap, key)
+			continue
+		}
+		targetMap[key] = mergePatchData(targetMap[key], value)
+	}
+	return targetMap
+}
+
+// CreateMergePatch 对比两个json文档生成merge patch；由于null表示删除，modified中值为null的key无法表示
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	originalData, err := decodeJson(string(original))
+	if err != nil {
+		return nil, err
+	}
+	modifiedData, err := decodeJson(string(modified))
+	if err != nil {
+		return nil, err
+	}
+	return marshalJson(diffMergePatch(originalData, modifiedData))
+}
+
+func diffMergePatch(original, modified any) any {
+	originalMap, originalOk := original.(map[string]any)
+	modifiedMap, modifiedOk := modified.(map[string]any)
+	if !originalOk || !modifiedOk {
+		return copyJsonData(modified)
+	}
+	patch := map[string]any{}
+	for key := range originalMap {
+		if _, exist := modifiedMap[key]; !exist {
+			patch[key] = nil
+		}
+	}
+	for key, value := range modifiedMap {
+		originalValue, exist := originalMap[key]
+		if !exist {
+			patch[key] = copyJsonData(value)
+			continue
+		}
+		if jsonEqual(originalValue, value) {
+			continue
+		}
+		if _, ok := value.(map[string]any); ok {
+			if _, ok = originalValue.(map[string]any); ok {
+				patch[key] = diffMergePatch(originalValue, value)
+				continue
+			}
+		}
+		patch[key] = copyJsonData(value)
+	}
+	return patch
+}
+
+// MergePatch 在当前对象上执行merge patch
+func (jsonObject *Object) MergePatch(patch string) error {
+	patchData, err := decodeJson(patch)
+	if err != nil {
+		return err
+	}
+	if _, ok := patchData.(map[string]any); !ok {
+		return fmt.Errorf("merge patch of json object must be an object")
+	}
+	jsonObject.ValueDeepMap = mergePatchData(jsonObject.ValueDeepMap, patchData).(map[string]any)
+	jsonObject.refreshValueMap()
+	return nil
+}
+
+// ApplyPatch 在当前对象上执行json patch，失败的时候对象不变
+func (jsonObject *Object) ApplyPatch(patch Patch) error {
+	var document any = jsonObject.ValueDeepMap
+	if nil == jsonObject.ValueDeepMap {
+		document = map[string]any{}
+	}
+	result, err := patch.applyData(document)
+	if err != nil {
+		return err
+	}
+	resultMap, ok := result.(map[string]any)
+	if !ok {
+		return fmt.Errorf("json object can not be replaced by %v", toJsonString(result))
+	}
+	jsonObject.ValueDeepMap = resultMap
+	jsonObject.refreshValueMap()
+	return nil
+}
+
+// Diff 生成从当前对象修改为modified的json patch
+func (jsonObject *Object) Diff(modified *Object) Patch {
+	return diffData(objectData(jsonObject), objectData(modified))
+}
+
+// DiffMergePatch 生成从当前对象修改为modified的merge patch
+func (jsonObject *Object) DiffMergePatch(modified *Object) string {
+	return toJsonString(diffMergePatch(objectData(jsonObject), objectData(modified)))
+}
+
+func objectData(jsonObject *Object) any {
+	if nil == jsonObject || nil == jsonObject.ValueDeepMap {
+		return map[string]any{}
+	}
+	return jsonObject.ValueDeepMap
+}
+
+// MergePatchObject 在结构体（或者map、分片）上执行merge patch，结构体通过util.ObjectToJson以及util.DataToObject转换，key的规则同其
+func MergePatchObject(targetPtrObj any, patch []byte) error {
+	document, err := objectToJsonData(targetPtrObj)
+	if err != nil {
+		return err
+	}
+	patchData, err := decodeJson(string(patch))
+	if err != nil {
+		return err
+	}
+	return jsonDataToObject(mergePatchData(document, patchData), targetPtrObj)
+}
+
+// ApplyPatchObject 在结构体（或者map、分片）上执行json patch，失败的时候不修改
+func ApplyPatchObject(targetPtrObj any, patch Patch) error {
+	document, err := objectToJsonData(targetPtrObj)
+	if err != nil {
+		return err
+	}
+	result, err := patch.applyData(document)
+	if err != nil {
+		return err
+	}
+	return jsonDataToObject(result, targetPtrObj)
+}
+
+// DiffObject 对比两个结构体（或者map、分片）生成json patch
+func DiffObject(original, modified any) (Patch, error) {
+	originalData, err := objectToJsonData(original)
+	if err != nil {
+		return nil, err
+	}
+	modifiedData, err := objectToJsonData(modified)
+	if err != nil {
+		return nil, err
+	}
+	return diffData(originalData, modifiedData), nil
+}
+
+func objectToJsonData(object any) (data any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			data, err = nil, fmt.Errorf("convert %T to json failed: %v", object, recovered)
+		}
+	}()
+
+	value := reflect.ValueOf(object)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, fmt.Errorf("object is nil")
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct, reflect.Map:
+		if value.Kind() == reflect.Map && value.Len() == 0 {
+			return map[string]any{}, nil
+		}
+		return decodeJson(util.ObjectToJson(value.Interface()))
+	case reflect.Slice, reflect.Array:
+		return decodeJson(util.ObjectToJson(value.Interface()))
+	}
+	return nil, fmt.Errorf("type %v is not a struct, map or slice", value.Type())
+}
+
+// 转换到新建的对象中，成功后再替换目标对象，使patch中删除的属性生效；转换失败时候目标对象不变
+func jsonDataToObject(data any, targetPtrObj any) (err error) {
+	targetValue := reflect.ValueOf(targetPtrObj)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return fmt.Errorf("targetPtrObj type is not ptr")
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("convert %v to %v failed: %v", toJsonString(data), targetValue.Elem().Type(), recovered)
+		}
+	}()
+	newValue := reflect.New(targetValue.Elem().Type())
+	if err = util.DataToObject(data, newValue.Interface()); err != nil {
+		return err
+	}
+	targetValue.Elem().Set(newValue.Elem())
+	return nil
+}
+
+// json中的值是否相等：数字按照数值比较
+func jsonEqual(left, right any) bool {
+	switch leftData := left.(type) {
+	case map[string]any:
+		rightData, ok := right.(map[string]any)
+		if !ok || len(leftData) != len(rightData) {
+			return false
+		}
+		for key, value := range leftData {
+			rightValue, exist := rightData[key]
+			if !exist || !jsonEqual(value, rightValue) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		rightData, ok := right.([]any)
+		if !ok || len(leftData) != len(rightData) {
+			return false
+		}
+		for index := range leftData {
+			if !jsonEqual(leftData[index], rightData[index]) {
+				return false
+			}
+		}
+		return true
+	}
+	if leftNumber, ok := toNumber(left); ok {
+		rightNumber, ok := toNumber(right)
+		return ok && leftNumber == rightNumber
+	}
+	return left == right
+}
+
+func copyJsonData(data any) any {
+	switch value := data.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(value))
+		for key, item := range value {
+			result[key] = copyJsonData(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(value))
+		for index, item := range value {
+			result[index] = copyJsonData(item)
+		}
+		return result
+	}
+	return data
+}
+
+func sortedKeys(dataMap map[string]any) []string {
+	keys := make([]string, 0, len(dataMap))
+	for key := range dataMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,686 @@
+package json
+
+import (
+	encodingjson "encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/simonalong/gole/util"
+)
+
+/**
+ * JSONPath风格的路径，开头的$可以省略，只有$的为根节点：
+ *  1.对象的key：data.items、$.data.items、data['first.name']
+ *  2.数组的下标：items[0]、items[-1]（最后一个）
+ *  3.通配符：items[*].price、data.*
+ *  4.过滤：items[?(@.price > 10)]、items[?(@.name == 'apple' && @.stock)]、tags[?(@ != 'old')]
+ *     支持的比较：==、!=、>、>=、<、<=，值可以为数字、字符串（单引号或者双引号）、true、false、null；只有@.xx的为存在判断；条件之间支持&&、||（&&优先）
+ */
+
+const (
+	segmentKey = iota
+	segmentIndex
+	segmentWildcard
+	segmentFilter
+)
+
+type pathSegment struct {
+	kind   int
+	key    string
+	index  int
+	filter pathFilter
+}
+
+// 过滤条件：or之间为||，and之间为&&
+type pathFilter [][]pathCondition
+
+type pathCondition struct {
+	// @后面的路径，为空表示元素本身
+	path []pathSegment
+	// 为空表示存在判断
+	operator string
+	value    any
+}
+
+// 是否为确定的路径（没有通配符以及过滤）
+func isDefinitePath(segments []pathSegment) bool {
+	for _, segment := range segments {
+		if segment.kind == segmentWildcard || segment.kind == segmentFilter {
+			return false
+		}
+	}
+	return true
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	text := strings.TrimSpace(path)
+	if strings.HasPrefix(text, "$") {
+		text = text[1:]
+	}
+
+	var segments []pathSegment
+	for index := 0; index < len(text); {
+		switch text[index] {
+		case '.':
+			if index+1 < len(text) && text[index+1] == '.' {
+				return nil, fmt.Errorf("path %q: recursive descent is not supported", path)
+			}
+			index++
+			continue
+		case '[':
+			end := matchBracket(text, index)
+			if end < 0 {
+				return nil, fmt.Errorf("path %q: ']' is missing", path)
+			}
+			segment, err := parseBracket(strings.TrimSpace(text[index+1 : end]))
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %v", path, err)
+			}
+			segments = append(segments, segment)
+			index = end + 1
+		default:
+			end := index
+			for end < len(text) && text[end] != '.' && text[end] != '[' {
+				end++
+			}
+			name := text[index:end]
+			if name == "*" {
+				segments = append(segments, pathSegment{kind: segmentWildcard})
+			} else {
+				segments = append(segments, pathSegment{kind: segmentKey, key: name})
+			}
+			index = end
+		}
+	}
+	if len(segments) == 0 && !strings.HasPrefix(strings.TrimSpace(path), "$") {
+		return nil, fmt.Errorf("path %q is empty", path)
+	}
+	// 只有$的为根节点，segments为空
+	return segments, nil
+}
+
+// 与start处的"["匹配的"]"，忽略引号以及括号中的
+func matchBracket(text string, start int) int {
+	depth := 0
+	var quote byte
+	for index := start; index < len(text); index++ {
+		ch := text[index]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				index++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '[' || ch == '(':
+			depth++
+		case ch == ']' || ch == ')':
+			depth--
+			if depth == 0 && ch == ']' {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(content string) (pathSegment, error) {
+	switch {
+	case content == "*":
+		return pathSegment{kind: segmentWildcard}, nil
+	case strings.HasPrefix(content, "?"):
+		expression := strings.TrimSpace(content[1:])
+		if !strings.HasPrefix(expression, "(") || !strings.HasSuffix(expression, ")") {
+			return pathSegment{}, fmt.Errorf("filter %q must be like ?(@.xx == yy)", content)
+		}
+		filter, err := parseFilter(expression[1 : len(expression)-1])
+		if err != nil {
+			return pathSegment{}, err
+		}
+		return pathSegment{kind: segmentFilter, filter: filter}, nil
+	case isQuoted(content):
+		key, err := unquote(content)
+		if err != nil {
+			return pathSegment{}, err
+		}
+		return pathSegment{kind: segmentKey, key: key}, nil
+	}
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return pathSegment{}, fmt.Errorf("index %q is not a number", content)
+	}
+	return pathSegment{kind: segmentIndex, index: index}, nil
+}
+
+func isQuoted(text string) bool {
+	return len(text) >= 2 && (text[0] == '\'' || text[0] == '"') && text[len(text)-1] == text[0]
+}
+
+func unquote(text string) (string, error) {
+	if text[0] == '"' {
+		return strconv.Unquote(text)
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(text[1:len(text)-1], "\\'", "'"), "\\\\", "\\"), nil
+}
+
+func parseFilter(expression string) (pathFilter, error) {
+	var filter pathFilter
+	for _, orPart := range splitOutsideQuotes(expression, "||") {
+		var conditions []pathCondition
+		for _, andPart := range splitOutsideQuotes(orPart, "&&") {
+			condition, err := parseCondition(strings.TrimSpace(andPart))
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		filter = append(filter, conditions)
+	}
+	return filter, nil
+}
+
+func parseCondition(text string) (pathCondition, error) {
+	condition := pathCondition{}
+	left := text
+	for _, operator := range []string{"==", "!=", ">=", "<=", ">", "<"} {
+		parts := splitOutsideQuotes(text, operator)
+		if len(parts) != 2 {
+			continue
+		}
+		left = strings.TrimSpace(parts[0])
+		value, err := parseLiteral(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return condition, err
+		}
+		condition.operator, condition.value = operator, value
+		break
+	}
+
+	if !strings.HasPrefix(left, "@") {
+		return condition, fmt.Errorf("filter condition %q must start with @", text)
+	}
+	if left != "@" {
+		path, err := parsePath(left[1:])
+		if err != nil {
+			return condition, err
+		}
+		if !isDefinitePath(path) {
+			return condition, fmt.Errorf("filter condition %q must be a definite path", text)
+		}
+		condition.path = path
+	}
+	return condition, nil
+}
+
+func parseLiteral(text string) (any, error) {
+	switch {
+	case isQuoted(text):
+		return unquote(text)
+	case text == "true":
+		return true, nil
+	case text == "false":
+		return false, nil
+	case text == "null":
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("filter value %q is illegal", text)
+	}
+	return number, nil
+}
+
+// 按照separator拆分，忽略引号中的
+func splitOutsideQuotes(text string, separator string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for index := 0; index < len(text); index++ {
+		ch := text[index]
+		if quote != 0 {
+			if ch == '\\' {
+				index++
+			} else if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+		if ch == '\'' || ch == '"' {
+			quote = ch
+			continue
+		}
+		if strings.HasPrefix(text[index:], separator) {
+			// >=、<=中的>、<不作为单独的操作符
+			if (separator == ">" || separator == "<") && index+1 < len(text) && text[index+1] == '=' {
+				continue
+			}
+			if (separator == "=" || separator == "==") && index > 0 && strings.ContainsRune("!<>", rune(text[index-1])) {
+				continue
+			}
+			parts = append(parts, text[start:index])
+			start = index + len(separator)
+			index += len(separator) - 1
+		}
+	}
+	return append(parts, text[start:])
+}
+
+func (filter pathFilter) match(node any) bool {
+	for _, conditions := range filter {
+		matched := true
+		for _, condition := range conditions {
+			if !condition.match(node) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (condition pathCondition) match(node any) bool {
+	value, exist := node, true
+	if len(condition.path) > 0 {
+		values := findPath(node, condition.path)
+		if len(values) == 0 {
+			value, exist = nil, false
+		} else {
+			value = values[0]
+		}
+	}
+	if condition.operator == "" {
+		return exist
+	}
+	if !exist {
+		return condition.operator == "!="
+	}
+
+	result, comparable := compareValue(value, condition.value)
+	switch condition.operator {
+	case "==":
+		return comparable && result == 0
+	case "!=":
+		return !comparable || result != 0
+	case ">":
+		return comparable && result > 0
+	case ">=":
+		return comparable && result >= 0
+	case "<":
+		return comparable && result < 0
+	case "<=":
+		return comparable && result <= 0
+	}
+	return false
+}
+
+// 比较两个值：数字按照数值比较，字符串按照字典序比较，其他的只能判断是否相等
+func compareValue(left, right any) (int, bool) {
+	if nil == left || nil == right {
+		if left == right {
+			return 0, true
+		}
+		return 1, false
+	}
+	if leftNumber, ok := toNumber(left); ok {
+		rightNumber, ok := toNumber(right)
+		if !ok {
+			return 1, false
+		}
+		switch {
+		case leftNumber < rightNumber:
+			return -1, true
+		case leftNumber > rightNumber:
+			return 1, true
+		}
+		return 0, true
+	}
+	if leftText, ok := left.(string); ok {
+		rightText, ok := right.(string)
+		if !ok {
+			return 1, false
+		}
+		return strings.Compare(leftText, rightText), true
+	}
+	if reflect.DeepEqual(left, right) {
+		return 0, true
+	}
+	return 1, false
+}
+
+func toNumber(value any) (float64, bool) {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return util.ToFloat64(value), true
+	}
+	return 0, false
+}
+
+// 按照路径查找所有匹配的值
+func findPath(root any, segments []pathSegment) []any {
+	nodes := []any{root}
+	for _, segment := range segments {
+		var next []any
+		for _, node := range nodes {
+			next = append(next, children(node, segment)...)
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// 节点中匹配segment的子节点
+func children(node any, segment pathSegment) []any {
+	switch segment.kind {
+	case segmentKey:
+		if dataMap, ok := node.(map[string]any); ok {
+			if value, exist := dataMap[segment.key]; exist {
+				return []any{value}
+			}
+		}
+	case segmentIndex:
+		if list, ok := node.([]any); ok {
+			if index, exist := listIndex(list, segment.index); exist {
+				return []any{list[index]}
+			}
+		}
+	case segmentWildcard, segmentFilter:
+		var result []any
+		for _, child := range allChildren(node) {
+			if segment.kind == segmentWildcard || segment.filter.match(child.value) {
+				result = append(result, child.value)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+type childNode struct {
+	key   string
+	index int
+	value any
+}
+
+// 对象中的所有值（按照key排序）或者数组中的所有元素
+func allChildren(node any) []childNode {
+	var result []childNode
+	switch data := node.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			result = append(result, childNode{key: key, value: data[key]})
+		}
+	case []any:
+		for index, value := range data {
+			result = append(result, childNode{index: index, value: value})
+		}
+	}
+	return result
+}
+
+// 数组的下标，负数为从后往前
+func listIndex(list []any, index int) (int, bool) {
+	if index < 0 {
+		index += len(list)
+	}
+	return index, index >= 0 && index < len(list)
+}
+
+// 按照路径设置值，返回设置后的节点；确定的路径中不存在的节点会自动创建（下一段为下标的创建数组，否则创建对象），
+// 数组的下标最大为数组的长度（追加到最后）；通配符以及过滤只修改已经存在的节点
+func setPath(node any, segments []pathSegment, value any) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+	segment, rest := segments[0], segments[1:]
+	switch segment.kind {
+	case segmentKey:
+		dataMap, ok := node.(map[string]any)
+		if !ok {
+			if nil != node {
+				return node, fmt.Errorf("%v is not an object", util.ToString(node))
+			}
+			dataMap = map[string]any{}
+		}
+		child, err := setPath(dataMap[segment.key], rest, value)
+		if err != nil {
+			return node, err
+		}
+		dataMap[segment.key] = child
+		return dataMap, nil
+	case segmentIndex:
+		list, ok := node.([]any)
+		if !ok {
+			if nil != node {
+				return node, fmt.Errorf("%v is not an array", util.ToString(node))
+			}
+			list = []any{}
+		}
+		index := segment.index
+		if index < 0 {
+			if index += len(list); index < 0 {
+				return node, fmt.Errorf("index %d out of range %d", segment.index, len(list))
+			}
+		}
+		if index > len(list) {
+			return node, fmt.Errorf("index %d out of range %d", segment.index, len(list))
+		}
+		if index == len(list) {
+			list = append(list, nil)
+		}
+		child, err := setPath(list[index], rest, value)
+		if err != nil {
+			return node, err
+		}
+		list[index] = child
+		return list, nil
+	}
+
+	for _, child := range allChildren(node) {
+		if segment.kind == segmentFilter && !segment.filter.match(child.value) {
+			continue
+		}
+		newChild, err := setPath(child.value, rest, value)
+		if err != nil {
+			return node, err
+		}
+		switch data := node.(type) {
+		case map[string]any:
+			data[child.key] = newChild
+		case []any:
+			data[child.index] = newChild
+		}
+	}
+	return node, nil
+}
+
+// 按照路径删除，返回删除后的节点以及删除的个数
+func deletePath(node any, segments []pathSegment) (any, int) {
+	segment, rest := segments[0], segments[1:]
+	if len(rest) > 0 {
+		count := 0
+		for _, child := range pathChildren(node, segment) {
+			newChild, childCount := deletePath(child.value, rest)
+			count += childCount
+			switch data := node.(type) {
+			case map[string]any:
+				data[child.key] = newChild
+			case []any:
+				data[child.index] = newChild
+			}
+		}
+		return node, count
+	}
+
+	removed := pathChildren(node, segment)
+	switch data := node.(type) {
+	case map[string]any:
+		for _, child := range removed {
+			delete(data, child.key)
+		}
+	case []any:
+		removedIndexes := map[int]bool{}
+		for _, child := range removed {
+			removedIndexes[child.index] = true
+		}
+		list := make([]any, 0, len(data))
+		for index, value := range data {
+			if !removedIndexes[index] {
+				list = append(list, value)
+			}
+		}
+		return list, len(removed)
+	}
+	return node, len(removed)
+}
+
+// 节点中匹配segment的子节点，包括其key或者下标
+func pathChildren(node any, segment pathSegment) []childNode {
+	switch segment.kind {
+	case segmentKey:
+		if dataMap, ok := node.(map[string]any); ok {
+			if value, exist := dataMap[segment.key]; exist {
+				return []childNode{{key: segment.key, value: value}}
+			}
+		}
+	case segmentIndex:
+		if list, ok := node.([]any); ok {
+			if index, exist := listIndex(list, segment.index); exist {
+				return []childNode{{index: index, value: list[index]}}
+			}
+		}
+	default:
+		var result []childNode
+		for _, child := range allChildren(node) {
+			if segment.kind == segmentWildcard || segment.filter.match(child.value) {
+				result = append(result, child)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// 设置的值转换为json的数据：对象为map[string]any，数组为[]any，整数为int
+func toJsonData(value any) any {
+	switch value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, map[string]any, []any:
+		return value
+	}
+	content, err := encodingjson.Marshal(value)
+	if err != nil {
+		return value
+	}
+	data, err := decodeJson(string(content))
+	if err != nil {
+		return value
+	}
+	return data
+}
+
+// 解析json，整数解析为int（超出int64范围的为float64）
+func decodeJson(content string) (any, error) {
+	decoder := encodingjson.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	return normalizeNumber(data), nil
+}
+
+func normalizeNumber(data any) any {
+	switch value := data.(type) {
+	case encodingjson.Number:
+		if number, err := value.Int64(); err == nil {
+			return int(number)
+		}
+		number, _ := value.Float64()
+		return number
+	case map[string]any:
+		for key, item := range value {
+			value[key] = normalizeNumber(item)
+		}
+	case []any:
+		for index, item := range value {
+			value[index] = normalizeNumber(item)
+		}
+	}
+	return data
+}
+
+// 按照路径获取值：确定的路径返回对应的值，否则返回所有匹配的值
+func getPathValue(root any, path string) any {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil
+	}
+	values := findPath(root, segments)
+	if isDefinitePath(segments) {
+		if len(values) == 0 {
+			return nil
+		}
+		return values[0]
+	}
+	if nil == values {
+		return []any{}
+	}
+	return values
+}
+
+// 类型化获取的值：包含通配符或者过滤的路径取第一个匹配的值
+func firstPathValue(value any, path string) any {
+	segments, err := parsePath(path)
+	if err != nil || isDefinitePath(segments) {
+		return value
+	}
+	if values, ok := value.([]any); ok && len(values) > 0 {
+		return values[0]
+	}
+	return nil
+}
+
+func toArray(data any) []any {
+	var arrayResult = []any{}
+	if nil == data {
+		return arrayResult
+	}
+	if err := util.DataToObject(data, &arrayResult); err != nil {
+		return []any{}
+	}
+	return arrayResult
+}
+
+func toJsonString(data any) string {
+	content, err := marshalJson(data)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// 转换为json，不转义html字符
+func marshalJson(data any) ([]byte, error) {
+	var buffer strings.Builder
+	encoder := encodingjson.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSuffix(buffer.String(), "\n")), nil
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/json"
+)
+
+func TestArrayLoad(t *testing.T) {
+	jsonArray := json.Array{}
+	err := jsonArray.Load(`[{"name": "a", "price": 1.5}, {"name": "b", "price": 3}, 5, "str", null]`)
+	assert.Equal(t, err, nil)
+
+	assert.Equal(t, jsonArray.Len(), 5)
+	assert.Equal(t, jsonArray.Get(0), map[string]any{"name": "a", "price": 1.5})
+	assert.Equal(t, jsonArray.Get(2), 5)
+	assert.Equal(t, jsonArray.Get(-2), "str")
+	assert.Equal(t, jsonArray.Get(4), nil)
+	assert.Equal(t, jsonArray.Get(5), nil)
+	assert.Equal(t, jsonArray.GetObject(1).GetInt("price"), 3)
+	assert.Equal(t, jsonArray.GetObject(2) == nil, true)
+
+	emptyArray := json.Array{}
+	assert.Equal(t, emptyArray.Load("[]"), nil)
+	assert.Equal(t, emptyArray.IsEmpty(), true)
+	assert.Equal(t, emptyArray.Load(`{"a": 1}`) != nil, true)
+	assert.Equal(t, emptyArray.Load(`[1,`) != nil, true)
+}
+
+func TestArrayPath(t *testing.T) {
+	jsonArray := json.Array{}
+	err := jsonArray.Load(`[{"name": "a", "price": 1.5}, {"name": "b", "price": 3, "tags": ["x"]}]`)
+	assert.Equal(t, err, nil)
+
+	assert.Equal(t, jsonArray.GetPath("[1].name"), "b")
+	assert.Equal(t, jsonArray.GetPath("$[1].tags[0]"), "x")
+	assert.Equal(t, jsonArray.GetPath("[*].name"), []any{"a", "b"})
+	assert.Equal(t, jsonArray.GetPath("[?(@.price > 2)].name"), []any{"b"})
+	assert.Equal(t, jsonArray.GetPathFloat64("[0].price"), 1.5)
+	assert.Equal(t, jsonArray.GetPathString("[?(@.tags)].name"), "b")
+
+	assert.Equal(t, jsonArray.SetPath("[0].tags[0]", "y"), nil)
+	assert.Equal(t, jsonArray.SetPath("[2].name", "c"), nil)
+	assert.Equal(t, jsonArray.Len(), 3)
+	assert.Equal(t, jsonArray.SetPath("[4].name", "e") != nil, true)
+	assert.Equal(t, jsonArray.GetPath("$[2]"), map[string]any{"name": "c"})
+	assert.Equal(t, len(jsonArray.GetPath("$").([]any)), 3)
+	assert.Equal(t, jsonArray.SetPath("name", "d") != nil, true)
+
+	jsonArray.Add(map[string]int{"price": 9})
+	assert.Equal(t, jsonArray.GetPath("[-1]"), map[string]any{"price": 9})
+
+	assert.Equal(t, jsonArray.DeletePath("[2]"), 1)
+	assert.Equal(t, jsonArray.DeletePath("[*].price"), 3)
+	assert.Equal(t, jsonArray.ToJson(), `[{"name":"a","tags":["y"]},{"name":"b","tags":["x"]},{}]`)
+}
@@ -0,0 +1,227 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/json"
+)
+
+// RFC 7386 附录A中的例子
+func TestMergePatch(t *testing.T) {
+	cases := [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, item := range cases {
+		result, err := json.MergePatch([]byte(item[0]), []byte(item[1]))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, string(result), item[2])
+	}
+
+	_, err := json.MergePatch([]byte(`{"a":`), []byte(`{}`))
+	assert.Equal(t, err != nil, true)
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	original := `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`
+	modified := `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`
+
+	patch, err := json.CreateMergePatch([]byte(original), []byte(modified))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(patch), `{"author":{"familyName":null},"phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`)
+
+	result, err := json.MergePatch([]byte(original), patch)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(result), `{"author":{"givenName":"John"},"content":"This will be unchanged","phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`)
+}
+
+// RFC 6902 附录A中的例子
+func TestApplyPatch(t *testing.T) {
+	cases := [][3]string{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"child":{"grandchild":{}},"foo":"bar"}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		{`{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{`{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		{`{"a":1.0}`, `[{"op":"test","path":"/a","value":1}]`, `{"a":1}`},
+	}
+	for _, item := range cases {
+		patch, err := json.DecodePatch([]byte(item[1]))
+		assert.Equal(t, err, nil)
+		result, err := patch.Apply([]byte(item[0]))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, string(result), item[2])
+	}
+}
+
+func TestApplyPatchError(t *testing.T) {
+	cases := [][2]string{
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`},
+		{`{"foo":[1]}`, `[{"op":"add","path":"/foo/2","value":1}]`},
+		{`{"foo":[1]}`, `[{"op":"remove","path":"/foo/01"}]`},
+		{`{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`},
+		{`{"foo":1}`, `[{"op":"add","path":"foo","value":1}]`},
+	}
+	for _, item := range cases {
+		patch, err := json.DecodePatch([]byte(item[1]))
+		assert.Equal(t, err, nil)
+		_, err = patch.Apply([]byte(item[0]))
+		assert.Equal(t, err != nil, true)
+	}
+
+	patch, _ := json.DecodePatch([]byte(`[{"op":"add","path":"/a","value":1},{"op":"test","path":"/b","value":2}]`))
+	_, err := patch.Apply([]byte(`{}`))
+	patchError, ok := err.(*json.PatchError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, patchError.Index, 1)
+
+	for _, content := range []string{`{"op":"add"}`, `[{"op":"add","path":"/a"}]`, `[{"op":"move","path":"/a"}]`, `[{"op":"merge","path":"/a"}]`, `[{"op":"remove"}]`} {
+		_, err = json.DecodePatch([]byte(content))
+		assert.Equal(t, err != nil, true)
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	original := `{"name":"a","tags":["x","y"],"owner":{"id":1,"mail":"a@b.c"},"items":[1,2],"a/b":1}`
+	modified := `{"name":"b","tags":["x","z"],"owner":{"id":1,"phone":"123"},"items":[1,2,3],"a/b":1,"note":null}`
+
+	patch, err := json.CreatePatch([]byte(original), []byte(modified))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, patch.ToJson(), `[{"op":"replace","path":"/items","value":[1,2,3]},{"op":"replace","path":"/name","value":"b"},{"op":"add","path":"/note","value":null},{"op":"remove","path":"/owner/mail"},{"op":"add","path":"/owner/phone","value":"123"},{"op":"replace","path":"/tags/1","value":"z"}]`)
+
+	result, err := patch.Apply([]byte(original))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(result), `{"a/b":1,"items":[1,2,3],"name":"b","note":null,"owner":{"id":1,"phone":"123"},"tags":["x","z"]}`)
+
+	// 编码之后再解析
+	decoded, err := json.DecodePatch([]byte(patch.ToJson()))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, decoded.ToJson(), patch.ToJson())
+
+	same, _ := json.CreatePatch([]byte(original), []byte(original))
+	assert.Equal(t, same.ToJson(), `[]`)
+}
+
+func TestObjectPatch(t *testing.T) {
+	jsonObject := json.Object{}
+	_ = jsonObject.Load(`{"name":"a","owner":{"id":1,"mail":"a@b.c"},"tags":["x"]}`)
+
+	assert.Equal(t, jsonObject.MergePatch(`{"owner":{"mail":null,"phone":"123"},"tags":["y"]}`), nil)
+	assert.Equal(t, jsonObject.ToJson(), `{"name":"a","owner":{"id":1,"phone":"123"},"tags":["y"]}`)
+	assert.Equal(t, jsonObject.Get("owner.phone"), "123")
+	assert.Equal(t, jsonObject.Get("owner.mail"), nil)
+	assert.Equal(t, jsonObject.MergePatch(`[1]`) != nil, true)
+
+	patch, _ := json.DecodePatch([]byte(`[{"op":"add","path":"/tags/0","value":"w"},{"op":"move","from":"/name","path":"/title"}]`))
+	assert.Equal(t, jsonObject.ApplyPatch(patch), nil)
+	assert.Equal(t, jsonObject.GetArray("tags"), []any{"w", "y"})
+	assert.Equal(t, jsonObject.GetString("title"), "a")
+
+	// 失败的时候不修改
+	patch, _ = json.DecodePatch([]byte(`[{"op":"remove","path":"/title"},{"op":"test","path":"/owner/id","value":2}]`))
+	assert.Equal(t, jsonObject.ApplyPatch(patch) != nil, true)
+	assert.Equal(t, jsonObject.GetString("title"), "a")
+
+	modified := json.Object{}
+	_ = modified.Load(`{"title":"b","owner":{"id":1,"phone":"123"},"tags":["w","y"]}`)
+	assert.Equal(t, jsonObject.Diff(&modified).ToJson(), `[{"op":"replace","path":"/title","value":"b"}]`)
+	assert.Equal(t, jsonObject.DiffMergePatch(&modified), `{"title":"b"}`)
+}
+
+type patchOwner struct {
+	Id   int
+	Mail string
+}
+
+type patchUser struct {
+	Name  string
+	Age   int
+	Tags  []string
+	Owner patchOwner
+}
+
+func TestPatchObject(t *testing.T) {
+	user := patchUser{Name: "simon", Age: 10, Tags: []string{"a"}, Owner: patchOwner{Id: 1, Mail: "a@b.c"}}
+
+	assert.Equal(t, json.MergePatchObject(&user, []byte(`{"age":11,"owner":{"mail":null}}`)), nil)
+	assert.Equal(t, user, patchUser{Name: "simon", Age: 11, Tags: []string{"a"}, Owner: patchOwner{Id: 1}})
+
+	patch, _ := json.DecodePatch([]byte(`[{"op":"add","path":"/tags/-","value":"b"},{"op":"replace","path":"/name","value":"tom"}]`))
+	assert.Equal(t, json.ApplyPatchObject(&user, patch), nil)
+	assert.Equal(t, user, patchUser{Name: "tom", Age: 11, Tags: []string{"a", "b"}, Owner: patchOwner{Id: 1}})
+
+	patch, _ = json.DecodePatch([]byte(`[{"op":"replace","path":"/name","value":"bob"},{"op":"test","path":"/age","value":12}]`))
+	assert.Equal(t, json.ApplyPatchObject(&user, patch) != nil, true)
+	assert.Equal(t, user.Name, "tom")
+
+	modified := user
+	modified.Age = 12
+	modified.Tags = []string{"a", "c"}
+	diff, err := json.DiffObject(user, &modified)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, diff.ToJson(), `[{"op":"replace","path":"/age","value":12},{"op":"replace","path":"/tags/1","value":"c"}]`)
+
+	dataMap := map[string]any{"a": 1}
+	assert.Equal(t, json.MergePatchObject(&dataMap, []byte(`{"a":null,"b":"x"}`)), nil)
+	assert.Equal(t, dataMap, map[string]any{"b": "x"})
+
+	_, err = json.DiffObject(1, 2)
+	assert.Equal(t, err != nil, true)
+}
+
+type patchProfile struct {
+	Owner *patchOwner
+	Level *int
+	Nick  *string
+}
+
+// 测试：指针类型的字段
+func TestPatchObjectPointer(t *testing.T) {
+	level := 1
+	profile := patchProfile{Owner: &patchOwner{Id: 1, Mail: "a@b.c"}, Level: &level}
+
+	assert.Equal(t, json.MergePatchObject(&profile, []byte(`{"owner":{"mail":null},"level":2,"nick":"tom"}`)), nil)
+	assert.Equal(t, *profile.Owner, patchOwner{Id: 1})
+	assert.Equal(t, *profile.Level, 2)
+	assert.Equal(t, *profile.Nick, "tom")
+	// 原来的指针指向的值不变
+	assert.Equal(t, level, 1)
+
+	assert.Equal(t, json.MergePatchObject(&profile, []byte(`{"owner":null,"nick":null}`)), nil)
+	assert.Equal(t, profile.Owner == nil, true)
+	assert.Equal(t, profile.Nick == nil, true)
+	assert.Equal(t, *profile.Level, 2)
+
+	patch, _ := json.DecodePatch([]byte(`[{"op":"add","path":"/owner","value":{"id":3}}]`))
+	assert.Equal(t, json.ApplyPatchObject(&profile, patch), nil)
+	assert.Equal(t, *profile.Owner, patchOwner{Id: 3})
+
+	// 转换失败的时候返回错误，目标对象不变
+	assert.Equal(t, json.MergePatchObject(&profile, []byte(`[1]`)) != nil, true)
+	assert.Equal(t, *profile.Level, 2)
+}
@@ -0,0 +1,161 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/json"
+)
+
+var responseStr = `{
+    "code": 0,
+    "data": {
+        "total": 4,
+        "items": [
+            {"name": "apple", "price": 5.5, "stock": 10, "tags": ["fruit", "red"]},
+            {"name": "banana", "price": 3, "tags": ["fruit"]},
+            {"name": "pear", "price": 12, "stock": 0},
+            {"name": "first.item", "price": 20, "stock": 3}
+        ],
+        "owner": {"first.name": "simon"}
+    }
+}`
+
+type pathItem struct {
+	Name  string
+	Price float64
+	Stock int
+}
+
+func loadResponse(t *testing.T) *json.Object {
+	jsonObject := &json.Object{}
+	err := jsonObject.Load(responseStr)
+	assert.Equal(t, err, nil)
+	return jsonObject
+}
+
+func TestGetPath(t *testing.T) {
+	jsonObject := loadResponse(t)
+
+	assert.Equal(t, jsonObject.GetPath("code"), 0)
+	assert.Equal(t, jsonObject.GetPath("$.data.total"), 4)
+	assert.Equal(t, jsonObject.GetPath("data.items[2].price"), 12)
+	assert.Equal(t, jsonObject.GetPath("data.items[-1].name"), "first.item")
+	assert.Equal(t, jsonObject.GetPath("data.items[0].tags[1]"), "red")
+	assert.Equal(t, jsonObject.GetPath("data['owner']['first.name']"), "simon")
+	assert.Equal(t, jsonObject.GetPath(`data.owner["first.name"]`), "simon")
+
+	// 不存在或者不合法
+	assert.Equal(t, jsonObject.GetPath("data.items[9].price"), nil)
+	assert.Equal(t, jsonObject.GetPath("data.none.price"), nil)
+	assert.Equal(t, jsonObject.GetPath("data..price"), nil)
+	assert.Equal(t, jsonObject.GetPath("data.items[x]"), nil)
+
+	// 通配符
+	assert.Equal(t, jsonObject.GetPath("data.items[*].name"), []any{"apple", "banana", "pear", "first.item"})
+	assert.Equal(t, jsonObject.GetPath("data.items[*].stock"), []any{10, 0, 3})
+	assert.Equal(t, jsonObject.GetPath("data.owner.*"), []any{"simon"})
+	assert.Equal(t, jsonObject.GetPath("data.items[*].none"), []any{})
+}
+
+func TestGetPathFilter(t *testing.T) {
+	jsonObject := loadResponse(t)
+
+	assert.Equal(t, jsonObject.GetPath("data.items[?(@.price > 5)].name"), []any{"apple", "pear", "first.item"})
+	assert.Equal(t, jsonObject.GetPath("data.items[?(@.price <= 5.5)].name"), []any{"apple", "banana"})
+	assert.Equal(t, jsonObject.GetPath("data.items[?(@.name == 'banana')].price"), []any{3})
+	assert.Equal(t, jsonObject.GetPath(`data.items[?(@.name != "banana")].price`), []any{5.5, 12, 20})
+	assert.Equal(t, jsonObject.GetPath("data.items[?(@.stock)].name"), []any{"apple", "pear", "first.item"})
+	assert.Equal(t, jsonObject.GetPath("data.items[?(@.stock && @.price > 10)].name"), []any{"pear", "first.item"})
+	assert.Equal(t, jsonObject.GetPath("data.items[?(@.price < 4 || @.stock == 0)].name"), []any{"banana", "pear"})
+	assert.Equal(t, jsonObject.GetPath("data.items[?(@.tags[0] == 'fruit')].name"), []any{"apple", "banana"})
+	assert.Equal(t, jsonObject.GetPath("data.items[0].tags[?(@ != 'fruit')]"), []any{"red"})
+	assert.Equal(t, jsonObject.GetPath("data.items[?(@.name == 'a || b')].name"), []any{})
+}
+
+func TestGetPathTyped(t *testing.T) {
+	jsonObject := loadResponse(t)
+
+	assert.Equal(t, jsonObject.GetPathInt("data.items[2].price"), 12)
+	assert.Equal(t, jsonObject.GetPathInt64("data.total"), int64(4))
+	assert.Equal(t, jsonObject.GetPathUInt8("data.items[0].stock"), uint8(10))
+	assert.Equal(t, jsonObject.GetPathFloat64("data.items[0].price"), 5.5)
+	assert.Equal(t, jsonObject.GetPathString("data.items[1].name"), "banana")
+	assert.Equal(t, jsonObject.GetPathString("data.items[?(@.price > 10)].name"), "pear")
+	assert.Equal(t, jsonObject.GetPathBool("data.items[9].none"), false)
+	assert.Equal(t, jsonObject.GetPathArray("data.items[0].tags"), []any{"fruit", "red"})
+	assert.Equal(t, jsonObject.GetPathArray("data.items[*].stock"), []any{10, 0, 3})
+
+	item := pathItem{}
+	err := jsonObject.GetPathObject("data.items[0]", &item)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, item, pathItem{Name: "apple", Price: 5.5, Stock: 10})
+
+	var items []pathItem
+	err = jsonObject.GetPathObject("data.items[?(@.stock)]", &items)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(items), 3)
+	assert.Equal(t, items[2].Name, "first.item")
+}
+
+func TestSetPath(t *testing.T) {
+	jsonObject := loadResponse(t)
+
+	assert.Equal(t, jsonObject.SetPath("data.items[1].stock", 8), nil)
+	assert.Equal(t, jsonObject.GetPathInt("data.items[1].stock"), 8)
+	assert.Equal(t, jsonObject.Get("data.items[1].stock"), 8)
+
+	// 自动创建中间的对象以及数组
+	assert.Equal(t, jsonObject.SetPath("meta.page.size", 20), nil)
+	assert.Equal(t, jsonObject.GetPath("meta"), map[string]any{"page": map[string]any{"size": 20}})
+	assert.Equal(t, jsonObject.SetPath("meta.links[0].href", "/next"), nil)
+	assert.Equal(t, jsonObject.SetPath("meta.links[1]", "/last"), nil)
+	assert.Equal(t, jsonObject.GetPath("meta.links"), []any{map[string]any{"href": "/next"}, "/last"})
+	assert.Equal(t, jsonObject.SetPath("$['a.b'].c", true), nil)
+	assert.Equal(t, jsonObject.GetPath("['a.b'].c"), true)
+
+	// 通配符以及过滤只修改匹配到的
+	assert.Equal(t, jsonObject.SetPath("data.items[?(@.price > 10)].hot", true), nil)
+	assert.Equal(t, jsonObject.GetPath("data.items[*].hot"), []any{true, true})
+	assert.Equal(t, jsonObject.SetPath("data.items[*].price", 1), nil)
+	assert.Equal(t, jsonObject.GetPath("data.items[*].price"), []any{1, 1, 1, 1})
+
+	// 结构体转换为对象
+	assert.Equal(t, jsonObject.SetPath("data.items[-1]", pathItem{Name: "peach", Price: 2.5}), nil)
+	assert.Equal(t, jsonObject.GetPath("data.items[3]"), map[string]any{"Name": "peach", "Price": 2.5, "Stock": 0})
+
+	// 类型不匹配或者路径不合法
+	assert.Equal(t, jsonObject.SetPath("code.value", 1) != nil, true)
+	assert.Equal(t, jsonObject.SetPath("data.items.name", 1) != nil, true)
+	assert.Equal(t, jsonObject.SetPath("[0]", 1) != nil, true)
+	assert.Equal(t, jsonObject.SetPath("data.items[", 1) != nil, true)
+	assert.Equal(t, jsonObject.SetPath("$", 1) != nil, true)
+	// 数组的下标最大为数组的长度
+	assert.Equal(t, jsonObject.SetPath("meta.links[3]", 1) != nil, true)
+	assert.Equal(t, jsonObject.SetPath("meta.links[100000000]", 1) != nil, true)
+	assert.Equal(t, len(jsonObject.GetPathArray("meta.links")), 2)
+	assert.Equal(t, jsonObject.GetPath("code"), 0)
+
+	emptyObject := json.Object{}
+	assert.Equal(t, emptyObject.SetPath("a[0][0]", "x"), nil)
+	assert.Equal(t, emptyObject.SetPath("a[0][1]", "y"), nil)
+	assert.Equal(t, emptyObject.ToJson(), `{"a":[["x","y"]]}`)
+	assert.Equal(t, emptyObject.GetPath("$"), map[string]any{"a": []any{[]any{"x", "y"}}})
+}
+
+func TestDeletePath(t *testing.T) {
+	jsonObject := loadResponse(t)
+
+	assert.Equal(t, jsonObject.DeletePath("data.items[0].tags"), 1)
+	assert.Equal(t, jsonObject.GetPath("data.items[0].tags"), nil)
+	assert.Equal(t, jsonObject.Get("data.items[0].tags"), nil)
+	assert.Equal(t, jsonObject.DeletePath("data.items[?(@.stock == 0)]"), 1)
+	assert.Equal(t, jsonObject.GetPath("data.items[*].name"), []any{"apple", "banana", "first.item"})
+	assert.Equal(t, jsonObject.DeletePath("data.items[*].stock"), 2)
+	assert.Equal(t, jsonObject.GetPath("data.items[*].stock"), []any{})
+	assert.Equal(t, jsonObject.DeletePath("data.items[-1]"), 1)
+	assert.Equal(t, jsonObject.DeletePath("data.none"), 0)
+	assert.Equal(t, jsonObject.DeletePath("data..none"), 0)
+	assert.Equal(t, jsonObject.DeletePath("code"), 1)
+	assert.Equal(t, jsonObject.ToJson(), `{"data":{"items":[{"name":"apple","price":5.5},{"name":"banana","price":3,"tags":["fruit"]}],"owner":{"first.name":"simon"},"total":4}}`)
+}
@@ -0,0 +1,20 @@
+package logger
+
+import "github.com/simonalong/gole/config"
+
+func init() {
+	config.RegisterKeyMetadata(
+		config.KeyMetadata{Key: "gole.logger.level", Type: "string", Default: "info", Description: "root的日志级别：trace/debug/info/warn/error/fatal/panic"},
+		config.KeyMetadata{Key: "gole.logger.home", Type: "string", Default: "./logs/", Description: "日志文件目录"},
+		config.KeyMetadata{Key: "gole.logger.color.enable", Type: "bool", Default: "false", Description: "是否启用日志颜色"},
+		config.KeyMetadata{Key: "gole.logger.rotate.max-size", Type: "string", Default: "300MB", Description: "日志滚动的文件大小"},
+		config.KeyMetadata{Key: "gole.logger.rotate.max-history", Type: "duration", Default: "60d", Description: "日志文件最大保留时间"},
+		config.KeyMetadata{Key: "gole.logger.rotate.time", Type: "duration", Default: "1d", Description: "日志滚动的时间间隔"},
+		config.KeyMetadata{Key: "gole.logger.path.type", Type: "string", Default: "short", Description: "日志中代码路径的格式：full/short"},
+		config.KeyMetadata{Key: "gole.logger.group.*.level", Type: "string", Description: "分组的日志级别"},
+		config.KeyMetadata{Key: "gole.logger.dir", Type: "string", Deprecated: true, Replacement: "gole.logger.home"},
+		config.KeyMetadata{Key: "gole.logger.split.enable", Type: "bool", Deprecated: true, Replacement: "gole.logger.rotate.max-size"},
+		config.KeyMetadata{Key: "gole.logger.split.size", Type: "int", Deprecated: true, Replacement: "gole.logger.rotate.max-size"},
+		config.KeyMetadata{Key: "gole.logger.max.history", Type: "int", Deprecated: true, Replacement: "gole.logger.rotate.max-history"},
+	)
+}
@@ -0,0 +1,34 @@
+package server
+
+import "github.com/simonalong/gole/config"
+
+func init() {
+	config.RegisterKeyMetadata(
+		config.KeyMetadata{Key: "gole.api.prefix", Type: "string", Description: "api前缀"},
+		config.KeyMetadata{Key: "gole.application.name", Type: "string", Description: "应用名"},
+		config.KeyMetadata{Key: "gole.application.version", Type: "string", Description: "应用版本号"},
+		config.KeyMetadata{Key: "gole.server.enable", Type: "bool", Default: "false", Description: "是否启用web服务"},
+		config.KeyMetadata{Key: "gole.server.port", Type: "int", Default: "8080", Description: "端口号"},
+		config.KeyMetadata{Key: "gole.server.version", Type: "string", Default: "unknown", Description: "服务版本号"},
+		config.KeyMetadata{Key: "gole.server.gin.mode", Type: "string", Default: "release", Description: "gin的模式：debug/release/test"},
+		config.KeyMetadata{Key: "gole.server.gin.pprof.enable", Type: "bool", Default: "false", Description: "是否开启pprof"},
+		config.KeyMetadata{Key: "gole.server.cors.enable", Type: "bool", Default: "true", Description: "是否启用跨域配置"},
+		config.KeyMetadata{Key: "gole.server.request.print.enable", Type: "bool", Default: "false", Description: "是否打印请求"},
+		config.KeyMetadata{Key: "gole.server.request.print.level", Type: "string", Default: "debug", Description: "打印请求的日志级别"},
+		config.KeyMetadata{Key: "gole.server.request.print.include-uri", Type: "list", Description: "指定要打印请求的uri"},
+		config.KeyMetadata{Key: "gole.server.request.print.exclude-uri", Type: "list", Description: "指定不打印请求的uri"},
+		config.KeyMetadata{Key: "gole.server.response.print.enable", Type: "bool", Default: "false", Description: "是否打印请求和响应"},
+		config.KeyMetadata{Key: "gole.server.response.print.level", Type: "string", Default: "debug", Description: "打印请求和响应的日志级别"},
+		config.KeyMetadata{Key: "gole.server.response.print.include-uri", Type: "list", Description: "指定要打印请求和响应的uri"},
+		config.KeyMetadata{Key: "gole.server.response.print.exclude-uri", Type: "list", Description: "指定不打印请求和响应的uri"},
+		config.KeyMetadata{Key: "gole.server.exception.print.enable", Type: "bool", Default: "false", Description: "是否打印异常返回"},
+		config.KeyMetadata{Key: "gole.server.exception.print.exclude", Type: "list", Description: "不打印异常返回的httpStatus"},
+		config.KeyMetadata{Key: "gole.debug.enable", Type: "bool", Default: "true", Description: "是否启用调试相关的端点"},
+		config.KeyMetadata{Key: "gole.endpoint.health.enable", Type: "bool", Default: "false", Description: "是否启用健康检查端点"},
+		config.KeyMetadata{Key: "gole.endpoint.config.enable", Type: "bool", Default: "false", Description: "是否启用配置管理端点"},
+		config.KeyMetadata{Key: "gole.endpoint.config.mask.patterns", Type: "list", Description: "查看配置时候追加的脱敏规则，默认已包含password、secret、token等"},
+		config.KeyMetadata{Key: "gole.endpoint.config.update.allow-keys", Type: "list", Description: "允许通过/config/update修改的配置，支持通配符；没有配置则不限制"},
+		config.KeyMetadata{Key: "gole.endpoint.bean.enable", Type: "bool", Default: "false", Description: "是否启用bean管理端点"},
+		config.KeyMetadata{Key: "gole.swagger.enable", Type: "bool", Default: "false", Description: "是否启用swagger"},
+	)
+}
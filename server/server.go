@@ -291,6 +291,8 @@ func RegisterConfigWatchEndpoint(apiGole string) gin.IRoutes {
 	RegisterRoute(apiGole+"/config/values/yaml", HmGet, config.GetConfigDeepValues)
 	RegisterRoute(apiGole+"/config/value/:key", HmGet, config.GetConfigValue)
 	RegisterRoute(apiGole+"/config/update", HmPut, config.UpdateConfig)
+	RegisterRoute(apiGole+"/config/metadata", HmGet, config.GetConfigMetadata)
+	RegisterRoute(apiGole+"/config/history", HmGet, config.GetConfigHistory)
 	return engine
 }
 
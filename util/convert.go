@@ -652,22 +652,25 @@ func doInvokeValue(fieldMapValue reflect.Value, field reflect.StructField, field
 	}
 
 	if fieldValue.Kind() == reflect.Ptr {
-		fValue = fValue.Elem()
+		// 指针类型的字段：按照指向的类型转换后新建指针，值为null的保持nil
+		targetValue := valueToTarget(fValue, field.Type.Elem())
+		if !targetValue.IsValid() {
+			return
+		}
+		if targetValue.Kind() == reflect.Ptr {
+			targetValue = targetValue.Elem()
+		}
+		ptrValue := reflect.New(field.Type.Elem())
+		ptrValue.Elem().Set(targetValue.Convert(field.Type.Elem()))
+		fieldValue.Set(ptrValue)
+		return
 	}
 	targetValue := valueToTarget(fValue, field.Type)
 	if targetValue.IsValid() {
-		if fieldValue.Kind() == reflect.Ptr {
-			if targetValue.Kind() == reflect.Ptr {
-				fieldValue.Elem().FieldByName(field.Name).Set(targetValue.Elem().Convert(field.Type))
-			} else {
-				fieldValue.Elem().FieldByName(field.Name).Set(targetValue.Convert(field.Type))
-			}
+		if targetValue.Kind() == reflect.Ptr {
+			fieldValue.Set(targetValue.Elem().Convert(field.Type))
 		} else {
-			if targetValue.Kind() == reflect.Ptr {
-				fieldValue.Set(targetValue.Elem().Convert(field.Type))
-			} else {
-				fieldValue.Set(targetValue.Convert(field.Type))
-			}
+			fieldValue.Set(targetValue.Convert(field.Type))
 		}
 	}
 }
@@ -918,8 +921,11 @@ func doObjectChange(objType reflect.Type, object any) any {
 	}
 	objKind := objType.Kind()
 	if objKind == reflect.Ptr {
-		objKind = objType.Elem().Kind()
 		objValue := reflect.ValueOf(object)
+		// 空指针和nil一样不输出
+		if objValue.IsNil() {
+			return nil
+		}
 		return doObjectChange(objType.Elem(), objValue.Elem().Interface())
 	}
 	if objKind == reflect.Int || objKind == reflect.Int8 || objKind == reflect.Int16 || objKind == reflect.Int32 || objKind == reflect.Int64 {
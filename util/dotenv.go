@@ -0,0 +1,94 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+/**
+ *  1.dotenv ----> map
+ *  2.dotenv ----> properties
+ */
+
+// DotenvToMap .env格式转换为map（key为原始的变量名），支持如下格式
+//   - KEY=value、export KEY=value
+//   - KEY="value"：双引号中支持\n、\t、\"、\\转义，支持跨行
+//   - KEY='value'：单引号中的内容原样保留
+//   - #开头的为注释；没有引号的值中" #"后面的为注释，有引号的值中闭合的引号后面的为注释
+func DotenvToMap(contentOfDotenv string) (map[string]any, error) {
+	resultMap := make(map[string]any)
+	scanner := bufio.NewScanner(strings.NewReader(contentOfDotenv))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		index := strings.Index(line, "=")
+		if index <= 0 {
+			return nil, &ConvertError{errMsg: fmt.Sprintf("dotenv格式错误，第%d行不是KEY=value格式：%s", lineNum, line)}
+		}
+		key := strings.TrimSpace(line[:index])
+		value := strings.TrimSpace(line[index+1:])
+
+		switch {
+		case strings.HasPrefix(value, "\""):
+			// 双引号的值可以跨行
+			end := closingQuoteIndex(value[1:])
+			for end < 0 {
+				if !scanner.Scan() {
+					return nil, &ConvertError{errMsg: fmt.Sprintf("dotenv格式错误，第%d行双引号没有闭合：%s", lineNum, key)}
+				}
+				lineNum++
+				value += NewLine + scanner.Text()
+				end = closingQuoteIndex(value[1:])
+			}
+			value = unescapeDotenv(value[1 : end+1])
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, &ConvertError{errMsg: fmt.Sprintf("dotenv格式错误，第%d行单引号没有闭合：%s", lineNum, key)}
+			}
+			value = value[1 : end+1]
+		default:
+			if commentIndex := strings.Index(value, " #"); commentIndex >= 0 {
+				value = strings.TrimSpace(value[:commentIndex])
+			}
+		}
+		resultMap[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return resultMap, nil
+}
+
+func DotenvToProperties(contentOfDotenv string) (string, error) {
+	dataMap, err := DotenvToMap(contentOfDotenv)
+	if err != nil {
+		return "", err
+	}
+	return MapToProperties(dataMap)
+}
+
+// 第一个没有被转义的双引号的位置，没有则返回-1
+func closingQuoteIndex(value string) int {
+	for index := 0; index < len(value); index++ {
+		if value[index] == '\\' {
+			index++
+			continue
+		}
+		if value[index] == '"' {
+			return index
+		}
+	}
+	return -1
+}
+
+func unescapeDotenv(value string) string {
+	return strings.NewReplacer("\\n", "\n", "\\t", "\t", "\\\"", "\"", "\\\\", "\\").Replace(value)
+}
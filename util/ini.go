@@ -0,0 +1,103 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+/**
+ *  1.ini ----> map
+ *  2.ini ----> properties
+ */
+
+// IniToMap ini格式转换为深层的map，支持如下格式
+//   - [section]、[section.sub]：分组，分组名中的点表示层级
+//   - key = value、key: value：分组下的配置，没有分组的配置在最外层
+//   - ;、#开头的为注释
+//   - 值两边的单引号和双引号会去掉
+func IniToMap(contentOfIni string) (map[string]any, error) {
+	resultMap := make(map[string]any)
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(contentOfIni))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, &ConvertError{errMsg: fmt.Sprintf("ini格式错误，第%d行分组没有闭合：%s", lineNum, line)}
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		index := strings.IndexAny(line, "=:")
+		if index <= 0 {
+			return nil, &ConvertError{errMsg: fmt.Sprintf("ini格式错误，第%d行不是key=value格式：%s", lineNum, line)}
+		}
+		key := strings.TrimSpace(line[:index])
+		if section != "" {
+			key = section + Dot + key
+		}
+		if !putDotKey(resultMap, key, unquoteValue(strings.TrimSpace(line[index+1:]))) {
+			return nil, &ConvertError{errMsg: fmt.Sprintf("ini格式错误，第%d行的key与其他配置冲突：%s", lineNum, key)}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return resultMap, nil
+}
+
+func IniToProperties(contentOfIni string) (string, error) {
+	dataMap, err := IniToMap(contentOfIni)
+	if err != nil {
+		return "", err
+	}
+	return MapToProperties(dataMap)
+}
+
+func IniToYaml(contentOfIni string) (string, error) {
+	dataMap, err := IniToMap(contentOfIni)
+	if err != nil {
+		return "", err
+	}
+	return ObjectToYaml(dataMap)
+}
+
+// 去掉值两边成对的单引号或者双引号
+func unquoteValue(value string) string {
+	if len(value) >= 2 {
+		if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// 按照key中的点放到深层的map中，比如：a.b=1 -> {a: {b: 1}}；与已有的值冲突则返回false
+func putDotKey(dataMap map[string]any, key string, value any) bool {
+	words := strings.Split(key, Dot)
+	for _, word := range words[:len(words)-1] {
+		child, exist := dataMap[word]
+		if !exist {
+			child = map[string]any{}
+			dataMap[word] = child
+		}
+		childMap, ok := child.(map[string]any)
+		if !ok {
+			return false
+		}
+		dataMap = childMap
+	}
+	if _, isMap := dataMap[words[len(words)-1]].(map[string]any); isMap {
+		return false
+	}
+	dataMap[words[len(words)-1]] = value
+	return true
+}
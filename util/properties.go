@@ -0,0 +1,183 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+/**
+ * properties格式的解析以及转义，同java的java.util.Properties：
+ *  1.#、!开头的行为注释
+ *  2.key与value之间的分隔符为第一个没有转义的=、:或者空白字符，分隔符两边的空白字符会被去掉
+ *  3.行尾为奇数个\的时候与下一行连接（下一行开头的空白字符会被去掉）
+ *  4.转义：\t、\n、\r、\f、\\uXXXX，其他的\x转义为x
+ */
+
+// 解析properties内容为key、value对，按照出现的顺序排列，相同的key保留多个
+func parsePropertiesEntries(contentOfProperties string) ([]StringPair, error) {
+	var pairs []StringPair
+	lines := splitPropertiesLines(contentOfProperties)
+	for index := 0; index < len(lines); index++ {
+		line := strings.TrimLeft(lines[index], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// 连接下一行
+		for endsWithContinuation(line) && index+1 < len(lines) {
+			index++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[index], " \t\f")
+		}
+		if endsWithContinuation(line) {
+			line = line[:len(line)-1]
+		}
+
+		key, value := splitPropertiesLine(line)
+		unescapedKey, err := unescapeProperties(key)
+		if err != nil {
+			return nil, err
+		}
+		unescapedValue, err := unescapeProperties(value)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, StringPair{Left: unescapedKey, Right: unescapedValue})
+	}
+	return pairs, nil
+}
+
+// 按照\n、\r、\r\n拆分行
+func splitPropertiesLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	return strings.Split(content, "\n")
+}
+
+// 行尾是否为奇数个\
+func endsWithContinuation(line string) bool {
+	count := 0
+	for index := len(line) - 1; index >= 0 && line[index] == '\\'; index-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// 拆分出key以及value（都还没有转义）
+func splitPropertiesLine(line string) (string, string) {
+	keyEnd := len(line)
+	for index := 0; index < len(line); index++ {
+		ch := line[index]
+		if ch == '\\' {
+			index++
+			continue
+		}
+		if ch == '=' || ch == ':' || ch == ' ' || ch == '\t' || ch == '\f' {
+			keyEnd = index
+			break
+		}
+	}
+	key := line[:keyEnd]
+	rest := strings.TrimLeft(line[keyEnd:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return key, rest
+}
+
+func unescapeProperties(text string) (string, error) {
+	if !strings.Contains(text, "\\") {
+		return text, nil
+	}
+	var builder strings.Builder
+	for index := 0; index < len(text); index++ {
+		ch := text[index]
+		if ch != '\\' || index+1 >= len(text) {
+			builder.WriteByte(ch)
+			continue
+		}
+		index++
+		switch text[index] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			if index+5 > len(text) {
+				return "", &ConvertError{errMsg: "malformed \\uxxxx encoding: " + text}
+			}
+			code, err := strconv.ParseUint(text[index+1:index+5], 16, 16)
+			if err != nil {
+				return "", &ConvertError{errMsg: "malformed \\uxxxx encoding: " + text}
+			}
+			index += 4
+			// utf-16的代理对
+			if code >= 0xD800 && code < 0xDC00 && index+6 < len(text) && text[index+1] == '\\' && text[index+2] == 'u' {
+				if low, err := strconv.ParseUint(text[index+3:index+7], 16, 16); err == nil && low >= 0xDC00 && low < 0xE000 {
+					builder.WriteRune(rune((code-0xD800)<<10+(low-0xDC00)) + 0x10000)
+					index += 6
+					continue
+				}
+			}
+			builder.WriteRune(rune(code))
+		default:
+			builder.WriteByte(text[index])
+		}
+	}
+	return builder.String(), nil
+}
+
+// 转义properties的key：\、空白字符、=、:以及开头的#、!
+func escapePropertiesKey(key string) string {
+	var builder strings.Builder
+	for index, ch := range key {
+		switch ch {
+		case ' ', '=', ':':
+			builder.WriteByte('\\')
+			builder.WriteRune(ch)
+		case '#', '!':
+			if index == 0 {
+				builder.WriteByte('\\')
+			}
+			builder.WriteRune(ch)
+		default:
+			writePropertiesChar(&builder, ch)
+		}
+	}
+	return builder.String()
+}
+
+// 转义properties的value：\、开头的空白字符以及换行等控制字符
+func escapePropertiesValue(value string) string {
+	var builder strings.Builder
+	leading := true
+	for _, ch := range value {
+		if ch == ' ' && leading {
+			builder.WriteString("\\ ")
+			continue
+		}
+		leading = false
+		writePropertiesChar(&builder, ch)
+	}
+	return builder.String()
+}
+
+func writePropertiesChar(builder *strings.Builder, ch rune) {
+	switch ch {
+	case '\\':
+		builder.WriteString("\\\\")
+	case '\t':
+		builder.WriteString("\\t")
+	case '\n':
+		builder.WriteString("\\n")
+	case '\r':
+		builder.WriteString("\\r")
+	case '\f':
+		builder.WriteString("\\f")
+	default:
+		builder.WriteRune(ch)
+	}
+}
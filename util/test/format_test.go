@@ -0,0 +1,44 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/util"
+)
+
+func TestTomlToMap(t *testing.T) {
+	dataMap, err := util.TomlToMap("title = \"gole\"\n[server]\nport = 8080\nhosts = [\"a\", \"b\"]\n")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap, map[string]any{"title": "gole", "server": map[string]any{"port": int64(8080), "hosts": []any{"a", "b"}}})
+
+	_, err = util.TomlToMap("title = ")
+	assert.Equal(t, err != nil, true)
+}
+
+func TestIniToMap(t *testing.T) {
+	content := "; 注释\nname = gole\n[server]\nport = 8080\n[server.http]\npath: \"/api\"\n"
+	dataMap, err := util.IniToMap(content)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap, map[string]any{"name": "gole", "server": map[string]any{"port": "8080", "http": map[string]any{"path": "/api"}}})
+
+	_, err = util.IniToMap("[server\nport=1")
+	assert.Equal(t, err != nil, true)
+}
+
+func TestDotenvToMap(t *testing.T) {
+	content := "# 注释\nexport NAME=gole # 注释\nSINGLE='a\\nb'\nDOUBLE=\"a\\nb\"\nMULTI=\"line1\nline2\"\n"
+	dataMap, err := util.DotenvToMap(content)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap, map[string]any{"NAME": "gole", "SINGLE": "a\\nb", "DOUBLE": "a\nb", "MULTI": "line1\nline2"})
+
+	// 闭合的引号后面的为注释，注释中的引号不影响值
+	dataMap, err = util.DotenvToMap("DOUBLE=\"a\\\"b\" # say \"hi\"\nSINGLE='a' # it's\n")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap, map[string]any{"DOUBLE": "a\"b", "SINGLE": "a"})
+
+	_, err = util.DotenvToMap("NAME=\"abc")
+	assert.Equal(t, err != nil, true)
+	_, err = util.DotenvToMap("NAME='abc")
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,132 @@
+package test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/util"
+)
+
+var specPropertiesFiles = []string{
+	"./resources/properties/spec/spring-application.properties",
+	"./resources/properties/spec/log4j.properties",
+	"./resources/properties/spec/kafka-server.properties",
+	"./resources/properties/spec/messages_zh_CN.properties",
+}
+
+// 测试：解析的结果与java规范的实现（magiconair/properties）一致
+func TestPropertiesToMapConformance(t *testing.T) {
+	loader := &properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+	for _, filePath := range specPropertiesFiles {
+		content, err := os.ReadFile(filePath)
+		assert.Equal(t, err, nil)
+
+		act, err := util.PropertiesToRawMap(string(content))
+		assert.Equal(t, err, nil, filePath)
+		// magiconair/properties中行尾\后面的\r\n只作为\r处理（java中为续行），这里统一为\n后对比
+		expect, err := loader.LoadBytes([]byte(strings.ReplaceAll(string(content), "\r\n", "\n")))
+		assert.Equal(t, err, nil, filePath)
+		assert.Equal(t, len(act), expect.Len(), filePath)
+		for key, value := range expect.Map() {
+			// magiconair/properties不合并utf-16的代理对（java中合并），在TestPropertiesToMapSpec中校验
+			if key == "emoji" {
+				continue
+			}
+			assert.Equal(t, act[key], value, filePath+": "+key)
+		}
+	}
+}
+
+// 测试：value整体为${key}的时候使用引用的key的值
+func TestPropertiesToMapAlias(t *testing.T) {
+	content := "a.port=8080\nb.port=${a.port}\nc.port=${b.port}\nd.port=${x.port}\ne.port=${e.port}\nf.url=http://${a.port}\ng.port=${a.port:80}\n"
+	act, err := util.PropertiesToMap(content)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, act, map[string]any{
+		"a.port": "8080",
+		"b.port": "8080",
+		"c.port": "8080",
+		"d.port": "${x.port}",
+		"e.port": "${e.port}",
+		"f.url":  "http://${a.port}",
+		"g.port": "${a.port:80}",
+	})
+
+	raw, _ := util.PropertiesToRawMap(content)
+	assert.Equal(t, raw["b.port"], "${a.port}")
+}
+
+// 测试：分隔符、续行、转义以及注释
+func TestPropertiesToMapSpec(t *testing.T) {
+	content, _ := os.ReadFile("./resources/properties/spec/spring-application.properties")
+	act, err := util.PropertiesToMap(string(content))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, act["server.port"], "8080")
+	assert.Equal(t, act["server.servlet.context-path"], "/api")
+	assert.Equal(t, act["spring.datasource.username"], "root")
+	assert.Equal(t, act["spring.datasource.password"], "p@ss=word#1")
+	assert.Equal(t, act["management.endpoints.web.exposure.include"], "health,info,metrics")
+	assert.Equal(t, act["app.welcome"], "欢迎使用")
+	assert.Equal(t, act["app.path"], `C:\data\logs`)
+	assert.Equal(t, act["app.blank"], "")
+	assert.Equal(t, act["app.regex"], `^\d+\.\d+$`)
+	assert.Equal(t, act["app.placeholder"], "${spring.application.name}-${server.port}")
+
+	content, _ = os.ReadFile("./resources/properties/spec/messages_zh_CN.properties")
+	act, err = util.PropertiesToMap(string(content))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, act["greeting"], "你好，{0}")
+	assert.Equal(t, act["farewell message"], "再见")
+	assert.Equal(t, act["key:with=separators"], "value")
+	assert.Equal(t, act["emoji"], "😀")
+	assert.Equal(t, act["multi.line"], "first second third")
+	assert.Equal(t, act["escaped.backslash.end"], `value\`)
+	assert.Equal(t, act["odd.continuation"], `a\b`)
+	assert.Equal(t, act["tab"], "separated")
+	assert.Equal(t, act["only.key"], "")
+	assert.Equal(t, act["indented.key"], "indented value  ")
+	assert.Equal(t, act["last.line.continuation"], "end")
+
+	_, err = util.PropertiesToMap(`bad=\u12G4`)
+	assert.Equal(t, err != nil, true)
+}
+
+// 测试：写出的内容按照java规范读取后与原始数据一致
+func TestMapToPropertiesEscape(t *testing.T) {
+	dataMap := map[string]any{
+		"multi":          "line1\nline2\n  line3",
+		"path":           `C:\data\logs`,
+		"leading":        "  value",
+		"key with:sep=":  "v",
+		"#comment":       "not a comment",
+		"unicode":        "中文",
+		"url":            "jdbc:mysql://127.0.0.1:3306/db?a=1",
+		"list":           []any{"a", "b"},
+		"nested":         map[string]any{"port": 8080, "tab\tkey": "x"},
+		"empty":          "",
+		"trailing.slash": `end\`,
+	}
+	content, err := util.MapToProperties(dataMap)
+	assert.Equal(t, err, nil)
+
+	loader := &properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+	expect, err := loader.LoadBytes([]byte(content))
+	assert.Equal(t, err, nil)
+	act, err := util.PropertiesToMap(content)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(act), 13)
+	for key, value := range expect.Map() {
+		assert.Equal(t, act[key], value, key)
+	}
+	assert.Equal(t, act["multi"], "line1\nline2\n  line3")
+	assert.Equal(t, act["path"], `C:\data\logs`)
+	assert.Equal(t, act["leading"], "  value")
+	assert.Equal(t, act["key with:sep="], "v")
+	assert.Equal(t, act["#comment"], "not a comment")
+	assert.Equal(t, act["list[1]"], "b")
+	assert.Equal(t, act["nested.tab\tkey"], "x")
+	assert.Equal(t, act["trailing.slash"], `end\`)
+}
@@ -531,6 +531,24 @@ func TestDataToObject10(t *testing.T) {
 	test.Equal(t, "{\"Name\":\"inner_1\",\"Age\":1,\"DataMap\":{\"k1\":[{\"Name\":\"name1\",\"Age\":1},{\"Name\":\"name2\",\"Age\":2}],\"k2\":[{\"Name\":\"name1\",\"Age\":1},{\"Name\":\"name2\",\"Age\":2}]}}", util.ToJsonString(targetObj))
 }
 
+type ValueInnerEntityPtr struct {
+	Inner *ValueInnerEntity1
+	Age   *int
+	Name  *string
+}
+
+// 指针类型的字段
+func TestDataToObject11(t *testing.T) {
+	inner1 := map[string]any{}
+	inner1["inner"] = map[string]any{"name": "inner_1", "age": 1}
+	inner1["age"] = 2
+
+	var targetObj ValueInnerEntityPtr
+	test.Equal(t, util.DataToObject(inner1, &targetObj), nil)
+	test.Equal(t, "{\"Inner\":{\"Name\":\"inner_1\",\"Age\":1},\"Age\":2,\"Name\":null}", util.ToJsonString(targetObj))
+	test.Equal(t, "{\"age\":2,\"inner\":{\"age\":1,\"name\":\"inner_1\"}}", util.ObjectToJson(targetObj))
+}
+
 // strToObject
 func TestStrToObject1(t *testing.T) {
 	var targetObj int
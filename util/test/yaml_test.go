@@ -3,6 +3,7 @@ package test
 import (
 	"fmt"
 	"github.com/magiconair/properties"
+	"github.com/magiconair/properties/assert"
 	"github.com/rs/zerolog/log"
 	"github.com/simonalong/gole/test"
 	"github.com/simonalong/gole/util"
@@ -164,6 +165,45 @@ func TestPropertiesToMap5(t *testing.T) {
 	propertiesToMap(t, "./resources/properties/array1.properties")
 }
 
+func TestPropertiesMapToDeepMap(t *testing.T) {
+	dataMap := map[string]any{
+		"a.b":       12,
+		"a.c":       true,
+		"a.d":       "str",
+		"a.i":       "0123",
+		"a.j":       "yes",
+		"a.e[1]":    "e1",
+		"a.e[0]":    "e0",
+		"a.f[0].g":  "g0",
+		"a.f[1].g":  "g1",
+		"a.h[0][1]": "h01",
+	}
+
+	act := util.PropertiesMapToDeepMap(dataMap)
+	expect := map[string]any{
+		"a": map[string]any{
+			"b": 12,
+			"c": true,
+			"d": "str",
+			"e": []any{"e0", "e1"},
+			"f": []any{map[string]any{"g": "g0"}, map[string]any{"g": "g1"}},
+			"h": []any{[]any{nil, "h01"}},
+			"i": "0123",
+			"j": "yes",
+		},
+	}
+	assert.Equal(t, act, expect)
+
+	// 字符串的值不会再推断类型
+	assert.Equal(t, util.PropertiesMapToDeepMap(map[string]any{"a": "12", "b": "1.10"}), map[string]any{"a": "12", "b": "1.10"})
+}
+
+func TestDeepMapToPropertiesMap(t *testing.T) {
+	dataMap, err := util.YamlToMap("a:\n  b: 12\n  code: \"0123\"\n  e: [e0, {g: g1}]\n  n: null\n")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, util.DeepMapToPropertiesMap(dataMap), map[string]any{"a.b": 12, "a.code": "0123", "a.e[0]": "e0", "a.e[1].g": "g1"})
+}
+
 func propertiesToMap(t *testing.T, filePath string) {
 	bytes, err := os.ReadFile(filePath)
 	if err != nil {
@@ -271,3 +311,101 @@ func propertiesToYamlTest(t *testing.T, filePath string) {
 	act = strings.TrimSpace(act)
 	test.Equal(t, act, expect)
 }
+
+// 测试：锚点的引用以及合并
+func TestYamlAnchorAndMerge(t *testing.T) {
+	content, _ := os.ReadFile("./resources/yml/anchor.yml")
+	dataMap, err := util.YamlToMap(string(content))
+	assert.Equal(t, err, nil)
+
+	datasource := dataMap["datasource"].(map[string]any)
+	assert.Equal(t, datasource["master"], map[string]any{"host": "10.0.0.1", "timeout": "3s", "tags": []any{"a", "b"}})
+	// 多个合并的对象中前面的优先
+	assert.Equal(t, datasource["slave"], map[string]any{"host": "127.0.0.1", "timeout": "10s", "retry": 3, "tags": []any{"a", "b"}})
+	assert.Equal(t, datasource["enabled"], true)
+
+	property, err := util.YamlToProperties(string(content))
+	assert.Equal(t, err, nil)
+	propertyMap, _ := util.PropertiesToMap(property)
+	assert.Equal(t, propertyMap["datasource.master.tags[1]"], "b")
+	assert.Equal(t, propertyMap["datasource.slave.retry"], "3")
+
+	jsonStr, err := util.YamlToJson("base: &base {port: 80}\nweb:\n  <<: *base\n  name: web\n")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, jsonStr, `{"base":{"port":80},"web":{"name":"web","port":80}}`)
+	list, err := util.YamlToList("- &a {x: 1}\n- *a\n")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, list, []any{map[string]any{"x": 1}, map[string]any{"x": 1}})
+}
+
+// 测试："---"分隔的多个文档
+func TestYamlToMaps(t *testing.T) {
+	content := "---\na: 1\nb:\n  c: 2\n---\n---\nb:\n  d: 3\n"
+	documents, err := util.YamlToMaps(content)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, documents, []map[string]any{{"a": 1, "b": map[string]any{"c": 2}}, {"b": map[string]any{"d": 3}}})
+
+	// 合并为一个map，后面文档的优先级高
+	dataMap, err := util.YamlToMap(content)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap, map[string]any{"a": 1, "b": map[string]any{"c": 2, "d": 3}})
+
+	dataMap, err = util.YamlToMap("")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(dataMap), 0)
+}
+
+// 测试：解析失败时候返回出错的行号以及列号
+func TestYamlError(t *testing.T) {
+	cases := []struct {
+		content string
+		line    int
+		column  int
+	}{
+		{"a: 1\nb: c: d\n", 2, 5},
+		{"a: [1, 2\nb: 2\n", 1, 5},
+		{"a: 1\nb: *nope\n", 2, 4},
+		{"a: &x 1\nb:\n  <<: *x\n", 3, 7},
+		{"a: &a\n  b: *a\n", 2, 6},
+		{"a: 1\n---\n- x\n", 3, 1},
+	}
+	for _, c := range cases {
+		_, err := util.YamlToMap(c.content)
+		yamlError, ok := err.(*util.YamlError)
+		assert.Equal(t, ok, true, c.content)
+		assert.Equal(t, yamlError.Line, c.line, c.content)
+		assert.Equal(t, yamlError.Column, c.column, c.content)
+	}
+}
+
+// 测试：重复的key同yaml.v2，后面的值覆盖前面的值
+func TestYamlDuplicateKey(t *testing.T) {
+	dataMap, err := util.YamlToMap("a: {b: 1}\na: {c: 2}\nx: y\n")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap, map[string]any{"a": map[string]any{"c": 2}, "x": "y"})
+}
+
+// 测试：时间保持原始的字符串
+func TestYamlTimestamp(t *testing.T) {
+	dataMap, err := util.YamlToMap("date: 2024-01-02\ntime: 2024-01-02T15:04:05Z\nquoted: \"2024-01-02\"\nlist: [2024-01-03]\n")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap, map[string]any{"date": "2024-01-02", "time": "2024-01-02T15:04:05Z", "quoted": "2024-01-02", "list": []any{"2024-01-03"}})
+}
+
+// 测试：锚点引用指数级展开的内容报错
+func TestYamlExcessiveAliasing(t *testing.T) {
+	content := "a: &a [x, x, x, x, x, x, x, x, x, x]\n"
+	for index := 'b'; index <= 'i'; index++ {
+		previous := string(index - 1)
+		content += string(index) + ": &" + string(index) + " [*" + previous + ", *" + previous + ", *" + previous + ", *" + previous + ", *" + previous + ", *" + previous + ", *" + previous + ", *" + previous + ", *" + previous + ", *" + previous + "]\n"
+	}
+	_, err := util.YamlToMap(content)
+	yamlError, ok := err.(*util.YamlError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, yamlError.Msg, "document contains excessive aliasing")
+
+	// 正常的引用不受影响
+	dataMap, err := util.YamlToMap("base: &base {a: 1, b: 2}\nc1: *base\nc2: *base\nc3: {<<: *base, b: 3}\n")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap["c3"], map[string]any{"a": 1, "b": 3})
+}
@@ -0,0 +1,182 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/simonalong/gole/util"
+)
+
+var yamlDocumentContent = `# 应用配置
+gole:
+  # 服务
+  server:
+    port: 8080 # 端口
+    name: "gole-demo"
+
+  base: &base
+    host: localhost
+    timeout: 3s
+  redis:
+    <<: *base
+    addrs:
+    - 127.0.0.1:6379
+    - 127.0.0.2:6379
+
+# 其他配置
+other: x
+`
+
+// 测试：读取，支持锚点以及合并
+func TestYamlDocumentGet(t *testing.T) {
+	doc, err := util.LoadYamlDocument(yamlDocumentContent)
+	assert.Equal(t, err, nil)
+
+	value, exist := doc.Get("gole.server.port")
+	assert.Equal(t, exist, true)
+	assert.Equal(t, value, 8080)
+	value, _ = doc.Get("gole.redis.host")
+	assert.Equal(t, value, "localhost")
+	value, _ = doc.Get("gole.redis.addrs[1]")
+	assert.Equal(t, value, "127.0.0.2:6379")
+	value, _ = doc.Get("gole.redis.addrs")
+	assert.Equal(t, value, []any{"127.0.0.1:6379", "127.0.0.2:6379"})
+	assert.Equal(t, doc.Has("gole.redis.password"), false)
+
+	// 没有修改的时候原样写回
+	content, err := doc.ToYaml()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, content, yamlDocumentContent)
+}
+
+// 测试：修改、插入以及删除后保留注释、顺序、锚点以及格式
+func TestYamlDocumentEdit(t *testing.T) {
+	doc, err := util.LoadYamlDocument(yamlDocumentContent)
+	assert.Equal(t, err, nil)
+
+	assert.Equal(t, doc.Set("gole.server.port", 8081), nil)
+	assert.Equal(t, doc.Set("gole.server.name", "gole-app"), nil)
+	// 合并的key在当前对象中覆盖，不修改锚点的节点
+	assert.Equal(t, doc.Set("gole.redis.timeout", "5s"), nil)
+	assert.Equal(t, doc.Insert("gole.redis.addrs[1]", "127.0.0.3:6379"), nil)
+	assert.Equal(t, doc.Remove("gole.redis.addrs[2]"), true)
+	assert.Equal(t, doc.Set("gole.server.tags", []string{"a", "b"}), nil)
+	assert.Equal(t, doc.Set("gole.log.level", "info"), nil)
+	assert.Equal(t, doc.Remove("other"), true)
+	assert.Equal(t, doc.Remove("gole.redis.host"), false)
+	assert.Equal(t, doc.Insert("gole.server.port", 1) != nil, true)
+	assert.Equal(t, doc.Set("gole.redis.addrs[5]", "x") != nil, true)
+	assert.Equal(t, doc.Set("gole.server.port.x", "x") != nil, true)
+
+	content, err := doc.ToYaml()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, content, `# 应用配置
+gole:
+  # 服务
+  server:
+    port: 8081 # 端口
+    name: "gole-app"
+    tags:
+    - a
+    - b
+
+  base: &base
+    host: localhost
+    timeout: 3s
+  redis:
+    <<: *base
+    addrs:
+    - 127.0.0.1:6379
+    - 127.0.0.3:6379
+    timeout: 5s
+  log:
+    level: info
+`)
+
+	value, _ := doc.Get("gole.base.timeout")
+	assert.Equal(t, value, "3s")
+	dataMap, err := doc.ToMap()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, dataMap["gole"].(map[string]any)["redis"].(map[string]any)["host"], "localhost")
+}
+
+// 测试：写回文件
+func TestYamlDocumentWriteFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "application.yml")
+	assert.Equal(t, os.WriteFile(filePath, []byte("gole:\n    server:\n        # 端口\n        port: 8080\n"), 0600), nil)
+
+	doc, err := util.LoadYamlDocumentFile(filePath)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, doc.Set("gole.server.port", 9090), nil)
+	assert.Equal(t, doc.WriteFile(filePath), nil)
+
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, string(content), "gole:\n    server:\n        # 端口\n        port: 9090\n")
+	info, _ := os.Stat(filePath)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0600))
+}
+
+var yamlMultiDocumentContent = `---
+gole:
+  profiles:
+    active: dev
+
+  server:
+    port: 8080
+---
+# 开发环境
+gole:
+  config:
+    activate:
+      on-profile: dev
+  server:
+    port: 8081
+---
+`
+
+// 测试：多个文档全部保留，按照文档读写
+func TestYamlDocumentMulti(t *testing.T) {
+	doc, err := util.LoadYamlDocument(yamlMultiDocumentContent)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, doc.DocumentCount(), 3)
+
+	content, _ := doc.ToYaml()
+	assert.Equal(t, content, yamlMultiDocumentContent)
+
+	value, _ := doc.Get("gole.server.port")
+	assert.Equal(t, value, 8080)
+	devDoc, err := doc.Document(1)
+	assert.Equal(t, err, nil)
+	value, _ = devDoc.Get("gole.server.port")
+	assert.Equal(t, value, 8081)
+
+	assert.Equal(t, devDoc.Set("gole.server.port", 9090), nil)
+	emptyDoc, _ := doc.Document(2)
+	assert.Equal(t, emptyDoc.Set("gole.server.port", 9091), nil)
+	content, _ = doc.ToYaml()
+	assert.Equal(t, content, `---
+gole:
+  profiles:
+    active: dev
+
+  server:
+    port: 8080
+---
+# 开发环境
+gole:
+  config:
+    activate:
+      on-profile: dev
+  server:
+    port: 9090
+---
+gole:
+  server:
+    port: 9091
+`)
+
+	_, err = doc.Document(3)
+	assert.Equal(t, err != nil, true)
+}
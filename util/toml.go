@@ -0,0 +1,48 @@
+package util
+
+import (
+	"log"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+/**
+ *  1.toml <---> map
+ *  2.toml ----> properties
+ *  3.toml ----> yaml
+ */
+
+func TomlToMap(contentOfToml string) (map[string]any, error) {
+	resultMap := make(map[string]any)
+	err := toml.Unmarshal([]byte(contentOfToml), &resultMap)
+	if err != nil {
+		log.Printf("TomlToMap, error: %v, content: %v", err, contentOfToml)
+		return nil, err
+	}
+	return resultMap, nil
+}
+
+func TomlToProperties(contentOfToml string) (string, error) {
+	dataMap, err := TomlToMap(contentOfToml)
+	if err != nil {
+		return "", err
+	}
+	return MapToProperties(dataMap)
+}
+
+func TomlToYaml(contentOfToml string) (string, error) {
+	dataMap, err := TomlToMap(contentOfToml)
+	if err != nil {
+		return "", err
+	}
+	return ObjectToYaml(dataMap)
+}
+
+func MapToToml(dataMap map[string]any) (string, error) {
+	content, err := toml.Marshal(dataMap)
+	if err != nil {
+		log.Printf("MapToToml error: %v, content: %v", err, dataMap)
+		return "", &ConvertError{errMsg: "MapToToml error"}
+	}
+	return string(content), nil
+}
@@ -169,26 +169,58 @@ func JsonToMap(contentOfJson string) (map[string]any, error) {
 	return resultMap, nil
 }
 
+// YamlToMap yaml转换为map：展开锚点的引用（*xxx）以及合并（<<: *xxx）；"---"分隔的多个文档按照顺序合并，后面文档的优先级高；
+// 解析失败返回*YamlError，包含出错的行号以及列号
 func YamlToMap(contentOfYaml string) (map[string]any, error) {
-	resultMap := make(map[string]any)
-	err := yaml.Unmarshal([]byte(contentOfYaml), &resultMap)
+	documents, err := YamlToMaps(contentOfYaml)
 	if err != nil {
 		log.Printf("YamlToMap, error: %v, content: %v", err, contentOfYaml)
 		return nil, err
 	}
 
+	resultMap := make(map[string]any)
+	for _, document := range documents {
+		resultMap = deepMergeMap(resultMap, document)
+	}
 	return resultMap, nil
 }
 
+// YamlToMaps 解析"---"分隔的多个文档，每个文档为一个map（空的文档会被忽略），展开锚点的引用以及合并
+func YamlToMaps(contentOfYaml string) ([]map[string]any, error) {
+	nodes, err := parseYamlDocuments(contentOfYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []map[string]any
+	for _, node := range nodes {
+		data, err := yamlNodeToData(node)
+		if err != nil {
+			return nil, err
+		}
+		dataMap, ok := data.(map[string]any)
+		if !ok {
+			return nil, newYamlNodeError(node.Content[0], "document is not a map")
+		}
+		documents = append(documents, dataMap)
+	}
+	return documents, nil
+}
+
+// YamlToJson yaml转换为json，展开锚点的引用以及合并；多个文档的时候合并为一个对象（文档都需要为map）
 func YamlToJson(contentOfYaml string) (string, error) {
-	if contentOfYaml != "-" && strings.Contains(contentOfYaml, ":") {
-		return "", &ConvertError{errMsg: "the content is invalidate for json"}
+	nodes, err := parseYamlDocuments(contentOfYaml)
+	if err != nil {
+		log.Printf("YamlToJson, error: %v, content: %v", err, contentOfYaml)
+		return "", err
 	}
 
 	var data any
-	err := yaml.Unmarshal([]byte(contentOfYaml), &data)
-	if err != nil {
-		log.Printf("YamlToList, error: %v, content: %v", err, contentOfYaml)
+	if len(nodes) == 1 {
+		if data, err = yamlNodeToData(nodes[0]); err != nil {
+			return "", err
+		}
+	} else if data, err = YamlToMap(contentOfYaml); err != nil {
 		return "", err
 	}
 
@@ -208,32 +240,30 @@ func YamlToKvList(contentOfYaml string) ([]StringPair, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	propertiesLineWordList := GetPropertiesItemLineList(property)
-	var pairs []StringPair
-	for _, element := range propertiesLineWordList {
-		element = strings.TrimSpace(element)
-		if "" == element {
-			continue
-		}
-		values := strings.SplitN(element, SignEqual, 2)
-		pairs = append(pairs, StringPair{Left: values[0], Right: values[1]})
-	}
-
-	return pairs, nil
+	return parsePropertiesEntries(property)
 }
 
 func YamlToList(contentOfYaml string) ([]any, error) {
 	if !strings.HasPrefix(strings.TrimSpace(contentOfYaml), "-") {
 		return []any{}, &ConvertError{errMsg: "the content of yaml not start with '-'"}
 	}
-	var resultList []any
-	err := yaml.Unmarshal([]byte(contentOfYaml), &resultList)
+	nodes, err := parseYamlDocuments(contentOfYaml)
 	if err != nil {
 		log.Printf("YamlToList, error: %v, content: %v", err, contentOfYaml)
 		return nil, err
 	}
+	if len(nodes) != 1 {
+		return nil, &ConvertError{errMsg: "the content of yaml is not one document"}
+	}
 
+	data, err := yamlNodeToData(nodes[0])
+	if err != nil {
+		return nil, err
+	}
+	resultList, ok := data.([]any)
+	if !ok {
+		return nil, newYamlNodeError(nodes[0].Content[0], "document is not a list")
+	}
 	return resultList, nil
 }
 
@@ -257,43 +287,167 @@ func YamlCheck(content string) error {
 	return nil
 }
 
+// PropertiesToMap 解析properties内容为扁平化的map，规则同java的java.util.Properties（见properties.go），相同的key后面的生效；
+// value都为字符串，value整体为"${key}"并且key在内容中存在的，使用该key的值（可以多级引用），引用不存在或者循环引用的以及其他的占位符原样保留
 func PropertiesToMap(contentOfProperties string) (map[string]any, error) {
-	if !strings.Contains(contentOfProperties, "=") {
-		return nil, &ConvertError{errMsg: "the content is illegal for properties"}
+	resultMap, err := PropertiesToRawMap(contentOfProperties)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasMap := make(map[string]any)
+	for key, value := range resultMap {
+		if target, ok := propertiesAliasKey(value); ok {
+			if targetValue, exist := resolvePropertiesAlias(resultMap, target, map[string]bool{key: true}); exist {
+				aliasMap[key] = targetValue
+			}
+		}
+	}
+	for key, value := range aliasMap {
+		resultMap[key] = value
+	}
+	return resultMap, nil
+}
+
+// PropertiesToRawMap 解析properties内容为扁平化的map，同PropertiesToMap，但是占位符"${xxx}"都原样保留，由使用方（比如config包）在读取时解析
+func PropertiesToRawMap(contentOfProperties string) (map[string]any, error) {
+	pairs, err := parsePropertiesEntries(contentOfProperties)
+	if err != nil {
+		return nil, err
 	}
 
-	var keyChangeMap = make(map[string]string)
 	var resultMap = make(map[string]any)
-	propertiesLineWordList := GetPropertiesItemLineList(contentOfProperties)
-	for _, line := range propertiesLineWordList {
-		line = strings.TrimSpace(line)
-		if "" == line {
+	for _, pair := range pairs {
+		resultMap[pair.Left] = pair.Right
+	}
+	return resultMap, nil
+}
+
+// value整体为"${key}"时候返回引用的key
+func propertiesAliasKey(value any) (string, bool) {
+	valueStr, ok := value.(string)
+	if !ok || !strings.HasPrefix(valueStr, "${") || !strings.HasSuffix(valueStr, "}") {
+		return "", false
+	}
+	return valueStr[2 : len(valueStr)-1], true
+}
+
+func resolvePropertiesAlias(valueMap map[string]any, key string, visited map[string]bool) (any, bool) {
+	value, exist := valueMap[key]
+	if !exist || visited[key] {
+		return nil, false
+	}
+	if target, ok := propertiesAliasKey(value); ok {
+		visited[key] = true
+		return resolvePropertiesAlias(valueMap, target, visited)
+	}
+	return value, true
+}
+
+// PropertiesMapToDeepMap 将扁平化的map（比如：a.b[0].c=xx）转换为深层嵌套的map，值保持原样，不推断类型
+func PropertiesMapToDeepMap(valueMap map[string]any) map[string]any {
+	keys := make([]string, 0, len(valueMap))
+	for key := range valueMap {
+		keys = append(keys, key)
+	}
+	// 排序后处理，保证结果稳定
+	sort.Strings(keys)
+
+	resultMap := make(map[string]any)
+	for _, key := range keys {
+		var paths []any
+		for _, word := range strings.Split(key, Dot) {
+			name, indexes := peelArrayIndexes(word)
+			if name != "" {
+				paths = append(paths, name)
+			}
+			for _, index := range indexes {
+				paths = append(paths, index)
+			}
+		}
+		if len(paths) == 0 {
 			continue
 		}
+		resultMap = deepSet(resultMap, paths, valueMap[key]).(map[string]any)
+	}
+	return resultMap
+}
+
+// DeepMapToPropertiesMap 将深层嵌套的map转换为扁平化的map（比如：a.b[0].c），同PropertiesMapToDeepMap相反；
+// 与MapToProperties不同，叶子节点的值保持解析出来的类型，比如yaml中的"0123"仍然为字符串，0123为数字
+func DeepMapToPropertiesMap(dataMap map[string]any) map[string]any {
+	resultMap := make(map[string]any)
+	for key, value := range dataMap {
+		doDeepMapToPropertiesMap(resultMap, value, key)
+	}
+	return resultMap
+}
 
-		lineKVs := strings.SplitN(line, "=", 2)
-		key := lineKVs[0]
-		value := lineKVs[1]
+func doDeepMapToPropertiesMap(resultMap map[string]any, value any, prefix string) {
+	switch data := value.(type) {
+	case nil:
+		return
+	case map[string]any:
+		for key, item := range data {
+			doDeepMapToPropertiesMap(resultMap, item, prefix+Dot+key)
+		}
+	case []any:
+		for index, item := range data {
+			doDeepMapToPropertiesMap(resultMap, item, prefix+"["+strconv.Itoa(index)+"]")
+		}
+	default:
+		resultMap[prefix] = value
+	}
+}
 
-		if strings.Contains(value, "\n") || value == "" {
-			value = YamlNewLineDom + value
+// 按照路径设置值，路径中string为map的key，int为数组的下标
+func deepSet(parent any, paths []any, value any) any {
+	if len(paths) == 0 {
+		// 已经存在子节点的时候，不被叶子节点覆盖
+		if parent != nil && reflect.TypeOf(parent).Kind() != reflect.String && !IsBaseType(reflect.TypeOf(parent)) {
+			return parent
 		}
+		return value
+	}
 
-		if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
-			// 记录两个key，原始key和替代key
-			keyChangeMap[key] = value[2 : len(value)-1]
-			continue
+	switch path := paths[0].(type) {
+	case string:
+		parentMap, ok := parent.(map[string]any)
+		if !ok {
+			parentMap = make(map[string]any)
 		}
-		resultMap[key] = value
+		parentMap[path] = deepSet(parentMap[path], paths[1:], value)
+		return parentMap
+	case int:
+		parentList, ok := parent.([]any)
+		if !ok {
+			parentList = []any{}
+		}
+		for len(parentList) <= path {
+			parentList = append(parentList, nil)
+		}
+		parentList[path] = deepSet(parentList[path], paths[1:], value)
+		return parentList
 	}
+	return parent
+}
 
-	// 将两个key进行替换
-	for keyOriginal, keyNewChange := range keyChangeMap {
-		if value, exist := resultMap[keyNewChange]; exist {
-			resultMap[keyOriginal] = value
+// 拆分出数组名字以及下标，比如：a[0][1] -> a, [0, 1]
+func peelArrayIndexes(word string) (string, []int) {
+	var indexes []int
+	for strings.HasSuffix(word, "]") {
+		start := strings.LastIndex(word, "[")
+		if start < 0 {
+			break
+		}
+		index, err := strconv.Atoi(word[start+1 : len(word)-1])
+		if err != nil {
+			break
 		}
+		indexes = append([]int{index}, indexes...)
+		word = word[:start]
 	}
-	return resultMap, nil
+	return word, indexes
 }
 
 func propertiesAppendPrefixKey(key string, propertiesContent string) (string, error) {
@@ -369,33 +523,18 @@ func ObjectToYaml(value any) (string, error) {
 	return string(bytes2), nil
 }
 
-// MapToProperties 进行深层嵌套的map数据处理
+// MapToProperties 进行深层嵌套的map数据处理，key按照字典序排列；key以及value按照java的java.util.Properties的规则转义，
+// 比如：多行的value中的换行转义为\n，key中的=、:、空格转义为\=、\:、\ ，非ascii字符不转义（按照utf-8读取）
 func MapToProperties(dataMap map[string]any) (string, error) {
+	keys := make([]string, 0, len(dataMap))
+	for key := range dataMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	var propertyStrList []string
-	for key, value := range dataMap {
-		if value == nil {
-			continue
-		}
-		valueKind := reflect.TypeOf(value).Kind()
-		switch valueKind {
-		case reflect.Map:
-			{
-				propertyStrList = doMapToProperties(propertyStrList, value, prefixWithDOT("")+key)
-			}
-		case reflect.Array, reflect.Slice:
-			{
-				objectValue := reflect.ValueOf(value)
-				for index := 0; index < objectValue.Len(); index++ {
-					propertyStrList = doMapToProperties(propertyStrList, objectValue.Index(index).Interface(), prefixWithDOT("")+key+"["+strconv.Itoa(index)+"]")
-				}
-			}
-		case reflect.String:
-			objectValue := reflect.ValueOf(value)
-			objectValueStr := strings.ReplaceAll(objectValue.String(), "\n", "\\\n")
-			propertyStrList = append(propertyStrList, prefixWithDOT("")+key+SignEqual+objectValueStr)
-		default:
-			propertyStrList = append(propertyStrList, prefixWithDOT("")+key+SignEqual+fmt.Sprintf("%v", value))
-		}
+	for _, key := range keys {
+		propertyStrList = doMapToProperties(propertyStrList, dataMap[key], escapePropertiesKey(key))
 	}
 	resultStr := ""
 	for _, propertyStr := range propertyStrList {
@@ -603,6 +742,7 @@ func wordToNode(lineWordList []string, nodeList []YamlNode, parentNode *YamlNode
 	return lineWordList, nodeList
 }
 
+// prefix为已经转义的key
 func doMapToProperties(propertyStrList []string, value any, prefix string) []string {
 	if value == nil {
 		return propertyStrList
@@ -616,10 +756,13 @@ func doMapToProperties(propertyStrList []string, value any, prefix string) []str
 				return propertyStrList
 			}
 
-			for mapR := reflect.ValueOf(value).MapRange(); mapR.Next(); {
-				mapKey := mapR.Key().Interface()
-				mapValue := mapR.Value().Interface()
-				propertyStrList = doMapToProperties(propertyStrList, mapValue, prefixWithDOT(prefix)+fmt.Sprintf("%v", mapKey))
+			mapValue := reflect.ValueOf(value)
+			keys := mapValue.MapKeys()
+			sort.Slice(keys, func(i, j int) bool {
+				return fmt.Sprintf("%v", keys[i].Interface()) < fmt.Sprintf("%v", keys[j].Interface())
+			})
+			for _, mapKey := range keys {
+				propertyStrList = doMapToProperties(propertyStrList, mapValue.MapIndex(mapKey).Interface(), prefixWithDOT(prefix)+escapePropertiesKey(fmt.Sprintf("%v", mapKey.Interface())))
 			}
 		}
 	case reflect.Array, reflect.Slice:
@@ -629,13 +772,8 @@ func doMapToProperties(propertyStrList []string, value any, prefix string) []str
 				propertyStrList = doMapToProperties(propertyStrList, objectValue.Index(index).Interface(), prefix+"["+strconv.Itoa(index)+"]")
 			}
 		}
-	case reflect.String:
-		objectValue := reflect.ValueOf(value)
-		objectValueStr := strings.ReplaceAll(objectValue.String(), "\n", "\\\n")
-		propertyStrList = append(propertyStrList, prefix+SignEqual+objectValueStr)
 	default:
-		objectValue := fmt.Sprintf("%v", reflect.ValueOf(value))
-		propertyStrList = append(propertyStrList, prefix+SignEqual+objectValue)
+		propertyStrList = append(propertyStrList, prefix+SignEqual+escapePropertiesValue(fmt.Sprintf("%v", value)))
 	}
 	return propertyStrList
 }
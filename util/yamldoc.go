@@ -0,0 +1,643 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// YamlDocument 可编辑的yaml文档：按照路径读取、修改、插入以及删除节点，写回时候保留注释、key的顺序、锚点以及格式（缩进、空行、数组的缩进方式）；
+// YamlToMap以及properties的互转都会丢失注释以及顺序，需要将配置写回文件时候使用该文档，比如：
+//
+//	doc, _ := util.LoadYamlDocumentFile("./application.yml")
+//	_ = doc.Set("gole.server.port", 8081)
+//	_ = doc.WriteFile("./application.yml")
+//
+// 路径同properties的key：gole.server.port、gole.kafka.addrs[0]、gole.datasource.list[1].host；
+// 内容有多个文档（---分隔）时候全部保留，路径的读写针对当前的文档，默认为第一个，通过Document切换
+//
+// 节点使用yaml.v3的Node（yaml的解析同样基于yaml.v3）：YamlNode只用于properties生成yaml，只有key、值以及子节点，没有注释、锚点、样式以及位置，不能用于原样写回
+type YamlDocument struct {
+	// 当前的文档
+	root *yamlv3.Node
+	// 所有的文档，多个文档的时候共享
+	stream *yamlStream
+}
+
+type yamlStream struct {
+	documents []*yamlv3.Node
+	// 原始内容的行，用于还原空行
+	lines []string
+	// 缩进的空格数
+	indent int
+	// 数组的"- "是否与上级的key对齐（不缩进）
+	compactSequence bool
+	// 原始内容是否以"---"开头
+	explicitStart bool
+}
+
+// LoadYamlDocument 解析yaml内容为可编辑的文档，空内容为空文档
+func LoadYamlDocument(contentOfYaml string) (*YamlDocument, error) {
+	documents, err := decodeYamlStream(contentOfYaml)
+	if err != nil {
+		return nil, toYamlError(contentOfYaml, err)
+	}
+	if len(documents) == 0 {
+		documents = append(documents, &yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{{Kind: yamlv3.MappingNode, Tag: "!!map"}}})
+	}
+	compactSequence := false
+	for _, document := range documents {
+		if len(document.Content) == 0 {
+			document.Content = []*yamlv3.Node{{Kind: yamlv3.ScalarNode, Tag: "!!null"}}
+		}
+		clearMergeTag(document)
+		compactSequence = compactSequence || detectCompactSequence(document.Content[0])
+	}
+
+	lines := strings.Split(contentOfYaml, NewLine)
+	stream := &yamlStream{documents: documents, lines: lines, indent: detectYamlIndent(lines), compactSequence: compactSequence, explicitStart: strings.HasPrefix(contentOfYaml, "---")}
+	return &YamlDocument{root: documents[0], stream: stream}, nil
+}
+
+// LoadYamlDocumentFile 读取yaml文件为可编辑的文档
+func LoadYamlDocumentFile(filePath string) (*YamlDocument, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return LoadYamlDocument(string(content))
+}
+
+// DocumentCount 文档的个数
+func (doc *YamlDocument) DocumentCount() int {
+	return len(doc.stream.documents)
+}
+
+// Document 切换到第index个文档（从0开始），返回的文档与当前的文档共享内容，写回时候同样包括所有的文档
+func (doc *YamlDocument) Document(index int) (*YamlDocument, error) {
+	if index < 0 || index >= len(doc.stream.documents) {
+		return nil, fmt.Errorf("yaml document index %d out of range %d", index, len(doc.stream.documents))
+	}
+	return &YamlDocument{root: doc.stream.documents[index], stream: doc.stream}, nil
+}
+
+// Get 读取路径对应的值，对象为map[string]any，数组为[]any；支持锚点的引用以及合并（<<）
+func (doc *YamlDocument) Get(path string) (any, bool) {
+	node := doc.lookup(path)
+	if nil == node {
+		return nil, false
+	}
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Has 路径对应的节点是否存在
+func (doc *YamlDocument) Has(path string) bool {
+	return nil != doc.lookup(path)
+}
+
+// ToMap 当前的文档转换为map
+func (doc *YamlDocument) ToMap() (map[string]any, error) {
+	resultMap := make(map[string]any)
+	if err := doc.root.Decode(&resultMap); err != nil {
+		return nil, err
+	}
+	return resultMap, nil
+}
+
+// Set 修改路径对应的值，value可以为对象、数组；不存在的节点（包括上级节点）会追加到对应对象的最后，
+// 已经存在的节点保留其注释、锚点以及字符串的引号等格式
+func (doc *YamlDocument) Set(path string, value any) error {
+	parent, last, err := doc.parentOf(path)
+	if err != nil {
+		return err
+	}
+	valueNode, err := toYamlDocumentNode(value)
+	if err != nil {
+		return err
+	}
+
+	switch key := last.(type) {
+	case string:
+		if index := mappingKeyIndex(parent, key); index >= 0 {
+			replaceYamlNode(parent.Content[index+1], valueNode)
+			return nil
+		}
+		parent.Content = append(parent.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}, valueNode)
+	case int:
+		if key < len(parent.Content) {
+			replaceYamlNode(parent.Content[key], valueNode)
+			return nil
+		}
+		parent.Content = append(parent.Content, valueNode)
+	}
+	return nil
+}
+
+// Insert 插入节点：路径的最后为数组下标时候插入到数组的该位置（下标等于数组长度时候追加到最后），否则追加到对象的最后；节点已经存在则返回错误
+func (doc *YamlDocument) Insert(path string, value any) error {
+	parent, last, err := doc.parentOf(path)
+	if err != nil {
+		return err
+	}
+	valueNode, err := toYamlDocumentNode(value)
+	if err != nil {
+		return err
+	}
+
+	switch key := last.(type) {
+	case string:
+		if mappingKeyIndex(parent, key) >= 0 {
+			return fmt.Errorf("yaml node %s already exists", path)
+		}
+		parent.Content = append(parent.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}, valueNode)
+	case int:
+		content := append([]*yamlv3.Node{}, parent.Content[:key]...)
+		content = append(content, valueNode)
+		parent.Content = append(content, parent.Content[key:]...)
+	}
+	return nil
+}
+
+// Remove 删除路径对应的节点（连同其注释），返回是否删除；通过合并（<<）引用的节点不能删除
+func (doc *YamlDocument) Remove(path string) bool {
+	segments := parseYamlPath(path)
+	if len(segments) == 0 {
+		return false
+	}
+	parent := doc.root.Content[0]
+	if len(segments) > 1 {
+		parent = doc.lookupNode(segments[:len(segments)-1])
+	}
+	if nil == parent {
+		return false
+	}
+	parent = resolveYamlAlias(parent)
+
+	switch key := segments[len(segments)-1].(type) {
+	case string:
+		index := mappingKeyIndex(parent, key)
+		if parent.Kind != yamlv3.MappingNode || index < 0 {
+			return false
+		}
+		parent.Content = append(parent.Content[:index], parent.Content[index+2:]...)
+	case int:
+		if parent.Kind != yamlv3.SequenceNode || key >= len(parent.Content) {
+			return false
+		}
+		parent.Content = append(parent.Content[:key], parent.Content[key+1:]...)
+	}
+	return true
+}
+
+// ToYaml 转换为yaml内容，包括所有的文档
+func (doc *YamlDocument) ToYaml() (string, error) {
+	var buffer bytes.Buffer
+	for index, document := range doc.stream.documents {
+		if index > 0 || doc.stream.explicitStart {
+			buffer.WriteString("---" + NewLine)
+		}
+		if isEmptyYamlDocument(document) {
+			// 空的文档yaml.v3会写入一个空行
+			continue
+		}
+		encoder := yamlv3.NewEncoder(&buffer)
+		encoder.SetIndent(doc.stream.indent)
+		if err := encoder.Encode(document); err != nil {
+			return "", err
+		}
+		if err := encoder.Close(); err != nil {
+			return "", err
+		}
+	}
+	return doc.stream.restoreLayout(buffer.String())
+}
+
+func isEmptyYamlDocument(document *yamlv3.Node) bool {
+	node := document.Content[0]
+	return isYamlNullNode(node) && node.Value == "" && document.HeadComment == "" && document.FootComment == "" && node.HeadComment == "" && node.LineComment == "" && node.FootComment == ""
+}
+
+// WriteFile 写入文件，文件已经存在的话保留其权限
+func (doc *YamlDocument) WriteFile(filePath string) error {
+	content, err := doc.ToYaml()
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filePath); err == nil {
+		mode = info.Mode()
+	}
+	return os.WriteFile(filePath, []byte(content), mode)
+}
+
+// 路径转换为节点的路径：string为对象的key，int为数组的下标
+func parseYamlPath(path string) []any {
+	var segments []any
+	if path == "" {
+		return segments
+	}
+	for _, word := range strings.Split(path, Dot) {
+		name, indexes := peelArrayIndexes(word)
+		if name != "" {
+			segments = append(segments, name)
+		}
+		for _, index := range indexes {
+			segments = append(segments, index)
+		}
+	}
+	return segments
+}
+
+func (doc *YamlDocument) lookup(path string) *yamlv3.Node {
+	segments := parseYamlPath(path)
+	if len(segments) == 0 {
+		return nil
+	}
+	return doc.lookupNode(segments)
+}
+
+// 按照路径查找节点，对象中没有的key会在合并（<<）的对象中查找
+func (doc *YamlDocument) lookupNode(segments []any) *yamlv3.Node {
+	node := doc.root.Content[0]
+	for _, segment := range segments {
+		node = childOfYamlNode(resolveYamlAlias(node), segment)
+		if nil == node {
+			return nil
+		}
+	}
+	return resolveYamlAlias(node)
+}
+
+func childOfYamlNode(node *yamlv3.Node, segment any) *yamlv3.Node {
+	switch key := segment.(type) {
+	case string:
+		if node.Kind != yamlv3.MappingNode {
+			return nil
+		}
+		if index := mappingKeyIndex(node, key); index >= 0 {
+			return node.Content[index+1]
+		}
+		for _, merged := range mergedYamlNodes(node) {
+			if child := childOfYamlNode(merged, key); nil != child {
+				return child
+			}
+		}
+	case int:
+		if node.Kind == yamlv3.SequenceNode && key < len(node.Content) {
+			return node.Content[key]
+		}
+	}
+	return nil
+}
+
+// 查找路径上级的节点（不存在的上级节点会创建），返回上级节点以及路径的最后一段；
+// 通过合并（<<）以及引用（*xxx）得到的上级节点会复制一份到当前对象中，修改时候不影响锚点的节点
+func (doc *YamlDocument) parentOf(path string) (*yamlv3.Node, any, error) {
+	segments := parseYamlPath(path)
+	if len(segments) == 0 {
+		return nil, nil, fmt.Errorf("yaml path %q is illegal", path)
+	}
+
+	node := doc.root.Content[0]
+	if isYamlNullNode(node) {
+		// 空的文档
+		node.Kind, node.Tag, node.Value = yamlv3.MappingNode, "!!map", ""
+	}
+	for index, segment := range segments {
+		node = resolveYamlAlias(node)
+		next := segments[index]
+		if index < len(segments)-1 {
+			next = segments[index+1]
+		}
+		switch key := segment.(type) {
+		case string:
+			if node.Kind != yamlv3.MappingNode {
+				return nil, nil, fmt.Errorf("yaml path %q: %s is not an object", path, key)
+			}
+		case int:
+			if node.Kind != yamlv3.SequenceNode {
+				return nil, nil, fmt.Errorf("yaml path %q: index %d is not in an array", path, key)
+			}
+			if key > len(node.Content) {
+				return nil, nil, fmt.Errorf("yaml path %q: index %d out of range %d", path, key, len(node.Content))
+			}
+		}
+		if index == len(segments)-1 {
+			return node, segment, nil
+		}
+		node = childForWrite(node, segment, next)
+	}
+	return nil, nil, fmt.Errorf("yaml path %q is illegal", path)
+}
+
+// 获取用于修改的子节点，不存在则按照下一段路径的类型创建对象或者数组
+func childForWrite(node *yamlv3.Node, segment any, next any) *yamlv3.Node {
+	newChild := func() *yamlv3.Node {
+		if _, isIndex := next.(int); isIndex {
+			return &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+		}
+		return &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+	}
+
+	switch key := segment.(type) {
+	case string:
+		if index := mappingKeyIndex(node, key); index >= 0 {
+			node.Content[index+1] = detachYamlAlias(node.Content[index+1])
+			return node.Content[index+1]
+		}
+		child := newChild()
+		if merged := childOfYamlNode(node, key); nil != merged {
+			child = copyYamlNode(resolveYamlAlias(merged))
+		}
+		node.Content = append(node.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}, child)
+		return child
+	case int:
+		if key < len(node.Content) {
+			node.Content[key] = detachYamlAlias(node.Content[key])
+			return node.Content[key]
+		}
+		child := newChild()
+		node.Content = append(node.Content, child)
+		return child
+	}
+	return nil
+}
+
+// 通过引用（*xxx）修改的时候复制一份锚点的节点，修改时候不影响锚点的节点
+func detachYamlAlias(node *yamlv3.Node) *yamlv3.Node {
+	if node.Kind != yamlv3.AliasNode {
+		return node
+	}
+	newNode := copyYamlNode(resolveYamlAlias(node))
+	newNode.HeadComment, newNode.LineComment, newNode.FootComment = node.HeadComment, node.LineComment, node.FootComment
+	return newNode
+}
+
+// 对象中key的下标，不存在返回-1
+func mappingKeyIndex(node *yamlv3.Node, key string) int {
+	if node.Kind != yamlv3.MappingNode {
+		return -1
+	}
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if node.Content[index].Value == key && !isYamlMergeKey(node.Content[index]) {
+			return index
+		}
+	}
+	return -1
+}
+
+// 对象中合并（<<）的对象，按照优先级排列
+func mergedYamlNodes(node *yamlv3.Node) []*yamlv3.Node {
+	var merged []*yamlv3.Node
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if !isYamlMergeKey(node.Content[index]) {
+			continue
+		}
+		value := resolveYamlAlias(node.Content[index+1])
+		if value.Kind == yamlv3.SequenceNode {
+			for _, item := range value.Content {
+				merged = append(merged, resolveYamlAlias(item))
+			}
+		} else {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+func isYamlMergeKey(node *yamlv3.Node) bool {
+	return node.Kind == yamlv3.ScalarNode && node.Value == "<<" && node.Style == 0
+}
+
+func resolveYamlAlias(node *yamlv3.Node) *yamlv3.Node {
+	for nil != node && node.Kind == yamlv3.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// 去掉合并key的!!merge标签，否则写回的时候会带上该标签
+func clearMergeTag(node *yamlv3.Node) {
+	if isYamlMergeKey(node) {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		clearMergeTag(child)
+	}
+}
+
+// 复制节点，不复制锚点以及在原始内容中的位置
+func copyYamlNode(node *yamlv3.Node) *yamlv3.Node {
+	newNode := *node
+	newNode.Anchor = ""
+	newNode.Line, newNode.Column = 0, 0
+	newNode.Content = make([]*yamlv3.Node, len(node.Content))
+	for index, child := range node.Content {
+		if child.Kind == yamlv3.AliasNode {
+			newNode.Content[index] = child
+			continue
+		}
+		newNode.Content[index] = copyYamlNode(child)
+	}
+	return &newNode
+}
+
+func toYamlDocumentNode(value any) (*yamlv3.Node, error) {
+	if node, ok := value.(*yamlv3.Node); ok {
+		return node, nil
+	}
+	node := &yamlv3.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// 修改节点的值，保留节点的注释、锚点；标量的类型不变的时候保留原来的格式（引号、多行等）
+func replaceYamlNode(target *yamlv3.Node, value *yamlv3.Node) {
+	if target.Kind == yamlv3.AliasNode {
+		// 引用的节点替换为新的值，不修改锚点的节点
+		value.HeadComment, value.LineComment, value.FootComment = target.HeadComment, target.LineComment, target.FootComment
+		*target = *value
+		return
+	}
+	style := value.Style
+	if target.Kind == yamlv3.ScalarNode && value.Kind == yamlv3.ScalarNode && target.Tag == value.Tag {
+		style = target.Style
+		if style&(yamlv3.LiteralStyle|yamlv3.FoldedStyle) != 0 && !strings.Contains(value.Value, NewLine) {
+			style = value.Style
+		}
+	}
+	target.Kind, target.Tag, target.Value, target.Style, target.Content = value.Kind, value.Tag, value.Value, style, value.Content
+}
+
+// 原始内容的缩进：最小的非0缩进，默认2个空格
+func detectYamlIndent(lines []string) int {
+	indent := 0
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if blanks := len(line) - len(trimmed); blanks > 0 && (indent == 0 || blanks < indent) {
+			indent = blanks
+		}
+	}
+	if indent < 2 {
+		return 2
+	}
+	return indent
+}
+
+// 原始内容中对象下的数组是否不缩进，比如：
+//
+//	addrs:
+//	- 127.0.0.1
+func detectCompactSequence(node *yamlv3.Node) bool {
+	if node.Kind == yamlv3.MappingNode {
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			key, value := node.Content[index], node.Content[index+1]
+			if value.Kind == yamlv3.SequenceNode && value.Style&yamlv3.FlowStyle == 0 && len(value.Content) > 0 && value.Line > key.Line {
+				return value.Column == key.Column
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if child.Kind != yamlv3.AliasNode && detectCompactSequence(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// 写回时候yaml.v3会去掉空行，并且对象下的数组总是缩进；这里按照原始内容还原空行以及数组的缩进方式
+func (doc *yamlStream) restoreLayout(content string) (string, error) {
+	outputs, err := decodeYamlStream(content)
+	if err != nil || len(outputs) != len(doc.documents) {
+		return content, nil
+	}
+	lines := strings.Split(content, NewLine)
+
+	var entries []yamlEntry
+	for index, document := range doc.documents {
+		collectYamlEntries(document.Content[0], outputs[index].Content[0], 0, &entries)
+	}
+
+	if doc.compactSequence {
+		shifts := make([]int, len(lines))
+		for index, entry := range entries {
+			if !entry.sequence {
+				continue
+			}
+			end := len(lines)
+			for _, next := range entries[index+1:] {
+				if next.depth <= entry.depth {
+					end = next.startLine - 1
+					break
+				}
+			}
+			for line := entry.valueLine - 1; line < end && line < len(lines); line++ {
+				shifts[line] += doc.indent
+			}
+		}
+		for index, shift := range shifts {
+			if shift > 0 && strings.HasPrefix(lines[index], strings.Repeat(" ", shift)) {
+				lines[index] = lines[index][shift:]
+			}
+		}
+	}
+
+	blanks := map[int]int{}
+	for _, entry := range entries {
+		if entry.originLine > 0 {
+			if count := doc.blankLinesBefore(entry.originLine, entry.commentLines); count > 0 && entry.startLine > 1 {
+				blanks[entry.startLine-1] = count
+			}
+		}
+	}
+	var builder strings.Builder
+	for index, line := range lines {
+		builder.WriteString(strings.Repeat(NewLine, blanks[index]))
+		builder.WriteString(line)
+		if index < len(lines)-1 {
+			builder.WriteString(NewLine)
+		}
+	}
+	return builder.String(), nil
+}
+
+// 对象的key以及数组的元素
+type yamlEntry struct {
+	depth int
+	// 写回后的开始行（包括上面的注释），从1开始
+	startLine int
+	// 原始内容中的行，新增的节点为0
+	originLine   int
+	commentLines int
+	// 值为数组（不是行内的格式）时候，数组第一个元素的开始行
+	sequence  bool
+	valueLine int
+}
+
+// 同时遍历修改后的节点以及写回后重新解析的节点（结构相同），按照文档的顺序收集对象的key以及数组的元素
+func collectYamlEntries(node *yamlv3.Node, output *yamlv3.Node, depth int, entries *[]yamlEntry) {
+	if node.Kind != output.Kind || len(node.Content) != len(output.Content) {
+		return
+	}
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			key, outputKey, outputValue := node.Content[index], output.Content[index], output.Content[index+1]
+			entry := yamlEntry{
+				depth:        depth,
+				startLine:    outputKey.Line - yamlCommentLines(outputKey.HeadComment),
+				originLine:   key.Line,
+				commentLines: yamlCommentLines(key.HeadComment),
+			}
+			if outputValue.Kind == yamlv3.SequenceNode && outputValue.Style&yamlv3.FlowStyle == 0 && len(outputValue.Content) > 0 {
+				first := outputValue.Content[0]
+				entry.sequence, entry.valueLine = true, first.Line-yamlCommentLines(first.HeadComment)
+			}
+			*entries = append(*entries, entry)
+			collectYamlEntries(node.Content[index+1], outputValue, depth+1, entries)
+		}
+	case yamlv3.SequenceNode:
+		for index, item := range node.Content {
+			outputItem := output.Content[index]
+			*entries = append(*entries, yamlEntry{
+				depth:        depth,
+				startLine:    outputItem.Line - yamlCommentLines(outputItem.HeadComment),
+				originLine:   item.Line,
+				commentLines: yamlCommentLines(item.HeadComment),
+			})
+			collectYamlEntries(item, outputItem, depth+1, entries)
+		}
+	}
+}
+
+func yamlCommentLines(comment string) int {
+	if comment == "" {
+		return 0
+	}
+	return strings.Count(comment, NewLine) + 1
+}
+
+// 原始内容中节点（包括上面的注释）之前的空行数
+func (doc *yamlStream) blankLinesBefore(line int, commentLines int) int {
+	count := 0
+	for index := line - commentLines - 2; index >= 0 && index < len(doc.lines) && strings.TrimSpace(doc.lines[index]) == ""; index-- {
+		count++
+	}
+	if count == line-commentLines-1 {
+		// 文档开头的空行
+		return 0
+	}
+	return count
+}
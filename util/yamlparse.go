@@ -0,0 +1,303 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// YamlError yaml解析的错误，包含出错的行号以及列号（都从1开始）
+type YamlError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (yamlError *YamlError) Error() string {
+	return fmt.Sprintf("yaml: line %d, column %d: %s", yamlError.Line, yamlError.Column, yamlError.Msg)
+}
+
+// yaml.v3的语法错误只有行号，比如：yaml: line 3: did not find expected key
+var yamlLineErrorPattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+var yamlAnchorErrorPattern = regexp.MustCompile(`^yaml: unknown anchor '(.*)' referenced$`)
+
+// yaml 1.1中的布尔值（yaml.v3按照1.2只识别true、false），兼容之前yaml.v2的解析结果；y、n这种单个字母的不再作为布尔值
+var yaml11Bools = map[string]bool{
+	"yes": true, "Yes": true, "YES": true, "on": true, "On": true, "ON": true,
+	"no": false, "No": false, "NO": false, "off": false, "Off": false, "OFF": false,
+}
+
+func newYamlNodeError(node *yamlv3.Node, format string, args ...any) *YamlError {
+	return &YamlError{Line: node.Line, Column: node.Column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// 解析"---"分隔的多个文档，忽略空的文档
+func parseYamlDocuments(contentOfYaml string) ([]*yamlv3.Node, error) {
+	nodes, err := decodeYamlStream(contentOfYaml)
+	if err != nil {
+		return nil, toYamlError(contentOfYaml, err)
+	}
+	var documents []*yamlv3.Node
+	for _, document := range nodes {
+		if len(document.Content) == 0 || isYamlNullNode(document.Content[0]) {
+			continue
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+func isYamlNullNode(node *yamlv3.Node) bool {
+	return node.Kind == yamlv3.ScalarNode && node.ShortTag() == "!!null"
+}
+
+// 语法错误转换为YamlError：yaml.v3只提供行号，列号为该行中最短的出现同样错误的前缀的长度，即出错的字符所在的列
+func toYamlError(contentOfYaml string, err error) error {
+	if matches := yamlAnchorErrorPattern.FindStringSubmatch(err.Error()); len(matches) > 0 {
+		return unknownAnchorError(contentOfYaml, matches[1], err)
+	}
+	matches := yamlLineErrorPattern.FindStringSubmatch(err.Error())
+	if len(matches) == 0 {
+		return err
+	}
+	line, _ := strconv.Atoi(matches[1])
+	yamlError := &YamlError{Line: line, Column: 1, Msg: matches[2]}
+
+	lines := strings.Split(contentOfYaml, NewLine)
+	if line < 1 || line > len(lines) {
+		return yamlError
+	}
+	before := strings.Join(lines[:line-1], NewLine)
+	if line > 1 {
+		before += NewLine
+	}
+	errorLine := []rune(lines[line-1])
+	for column := 1; column <= len(errorLine); column++ {
+		_, prefixErr := decodeYamlStream(before + string(errorLine[:column]))
+		if nil == prefixErr {
+			continue
+		}
+		if prefixMatches := yamlLineErrorPattern.FindStringSubmatch(prefixErr.Error()); len(prefixMatches) > 0 && prefixMatches[1] == matches[1] && prefixMatches[2] == matches[2] {
+			yamlError.Column = column
+			break
+		}
+	}
+	return yamlError
+}
+
+// 引用不存在的锚点的位置：第一个引用该锚点的*xxx
+func unknownAnchorError(contentOfYaml string, anchor string, err error) error {
+	pattern := regexp.MustCompile(`\*` + regexp.QuoteMeta(anchor) + `(?:[\s,\]}]|$)`)
+	for index, line := range strings.Split(contentOfYaml, NewLine) {
+		if location := pattern.FindStringIndex(line); location != nil {
+			return &YamlError{Line: index + 1, Column: len([]rune(line[:location[0]])) + 1, Msg: fmt.Sprintf("unknown anchor '%s' referenced", anchor)}
+		}
+	}
+	return err
+}
+
+// 按照顺序解析所有的文档（包括空的文档），节点的行号为在整个内容中的行号
+func decodeYamlStream(content string) ([]*yamlv3.Node, error) {
+	var documents []*yamlv3.Node
+	decoder := yamlv3.NewDecoder(bytes.NewBufferString(content))
+	for {
+		document := &yamlv3.Node{}
+		if err := decoder.Decode(document); err != nil {
+			if errors.Is(err, io.EOF) {
+				return documents, nil
+			}
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+}
+
+// 通过锚点引用展开的节点数的下限，超过该值并且超过文档本身节点数的yamlMaxAliasRatio倍则报错，避免"billion laughs"这种指数级展开的内容
+const yamlMinAliasNodes = 100000
+const yamlMaxAliasRatio = 10
+
+// 锚点引用的展开
+type yamlExpander struct {
+	// 正在展开的锚点节点，用于发现引用自身的锚点
+	expanding map[*yamlv3.Node]bool
+	// 当前在几层引用中，以及通过引用展开的节点数
+	aliasDepth    int
+	aliasNodes    int
+	maxAliasNodes int
+}
+
+// 节点转换为数据：对象为map[string]any，数组为[]any；锚点的引用会展开为一份新的数据，合并（<<）的key被当前对象中的key覆盖
+func yamlNodeToData(node *yamlv3.Node) (any, error) {
+	maxAliasNodes := yamlMaxAliasRatio * countYamlNodes(node)
+	if maxAliasNodes < yamlMinAliasNodes {
+		maxAliasNodes = yamlMinAliasNodes
+	}
+	expander := &yamlExpander{expanding: map[*yamlv3.Node]bool{}, maxAliasNodes: maxAliasNodes}
+	return expander.expand(node)
+}
+
+// 文档本身（不展开引用）的节点数
+func countYamlNodes(node *yamlv3.Node) int {
+	count := 1
+	for _, child := range node.Content {
+		count += countYamlNodes(child)
+	}
+	return count
+}
+
+func (expander *yamlExpander) expand(node *yamlv3.Node) (any, error) {
+	if expander.aliasDepth > 0 {
+		if expander.aliasNodes++; expander.aliasNodes > expander.maxAliasNodes {
+			return nil, newYamlNodeError(node, "document contains excessive aliasing")
+		}
+	}
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return expander.expand(node.Content[0])
+	case yamlv3.AliasNode:
+		if expander.expanding[node.Alias] {
+			return nil, newYamlNodeError(node, "anchor '%s' references itself", node.Value)
+		}
+		expander.aliasDepth++
+		defer func() { expander.aliasDepth-- }()
+		return expander.expand(node.Alias)
+	case yamlv3.SequenceNode:
+		if node.Anchor != "" {
+			expander.expanding[node] = true
+			defer delete(expander.expanding, node)
+		}
+		list := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			data, err := expander.expand(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, data)
+		}
+		return list, nil
+	case yamlv3.MappingNode:
+		if node.Anchor != "" {
+			expander.expanding[node] = true
+			defer delete(expander.expanding, node)
+		}
+		return expander.mappingToMap(node)
+	case yamlv3.ScalarNode:
+		// 时间保持原始的字符串，比如：2024-01-02，同之前yaml.v2的解析结果
+		if node.ShortTag() == "!!timestamp" {
+			return node.Value, nil
+		}
+		var data any
+		if err := node.Decode(&data); err != nil {
+			return nil, newYamlNodeError(node, "%v", err)
+		}
+		if text, ok := data.(string); ok && node.Style == 0 && node.ShortTag() == "!!str" {
+			if boolValue, exist := yaml11Bools[text]; exist {
+				return boolValue, nil
+			}
+		}
+		return data, nil
+	}
+	return nil, newYamlNodeError(node, "unsupported yaml node")
+}
+
+func (expander *yamlExpander) mappingToMap(node *yamlv3.Node) (map[string]any, error) {
+	resultMap := make(map[string]any)
+	keyNodes := map[string]*yamlv3.Node{}
+	var merged []map[string]any
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		keyNode, valueNode := node.Content[index], node.Content[index+1]
+		if keyNode.Kind == yamlv3.ScalarNode && keyNode.Value == "<<" && keyNode.Style == 0 {
+			mergedMaps, err := expander.mergedMaps(valueNode)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, mergedMaps...)
+			continue
+		}
+
+		if keyNode.Kind != yamlv3.ScalarNode {
+			return nil, newYamlNodeError(keyNode, "mapping key is not a scalar")
+		}
+		key := keyNode.Value
+		// 重复的key同之前yaml.v2的解析结果：后面的覆盖前面的
+		if exist, ok := keyNodes[key]; ok {
+			log.Printf("yaml: line %d, column %d: mapping key %q already defined at line %d, the latter value is used", keyNode.Line, keyNode.Column, key, exist.Line)
+		}
+		keyNodes[key] = keyNode
+
+		value, err := expander.expand(valueNode)
+		if err != nil {
+			return nil, err
+		}
+		resultMap[key] = value
+	}
+
+	// 当前对象中的key优先，多个合并的对象中前面的优先
+	for _, mergedMap := range merged {
+		for key, value := range mergedMap {
+			if _, exist := resultMap[key]; !exist {
+				resultMap[key] = value
+			}
+		}
+	}
+	return resultMap, nil
+}
+
+// 合并（<<）的值：一个对象或者多个对象的数组
+func (expander *yamlExpander) mergedMaps(node *yamlv3.Node) ([]map[string]any, error) {
+	target := node
+	for target.Kind == yamlv3.AliasNode {
+		target = target.Alias
+	}
+	var items []*yamlv3.Node
+	switch target.Kind {
+	case yamlv3.MappingNode:
+		items = []*yamlv3.Node{node}
+	case yamlv3.SequenceNode:
+		items = target.Content
+	default:
+		return nil, newYamlNodeError(node, "merge value must be a map or a list of maps")
+	}
+	if target != node {
+		expander.aliasDepth++
+		defer func() { expander.aliasDepth-- }()
+	}
+
+	var mergedMaps []map[string]any
+	for _, item := range items {
+		data, err := expander.expand(item)
+		if err != nil {
+			return nil, err
+		}
+		dataMap, ok := data.(map[string]any)
+		if !ok {
+			return nil, newYamlNodeError(item, "merge value must be a map or a list of maps")
+		}
+		mergedMaps = append(mergedMaps, dataMap)
+	}
+	return mergedMaps, nil
+}
+
+// 深层合并map：right中的值覆盖left中的值，都为map的时候合并其中的key
+func deepMergeMap(left, right map[string]any) map[string]any {
+	for key, rightValue := range right {
+		leftMap, leftOk := left[key].(map[string]any)
+		rightMap, rightOk := rightValue.(map[string]any)
+		if leftOk && rightOk {
+			left[key] = deepMergeMap(leftMap, rightMap)
+			continue
+		}
+		left[key] = rightValue
+	}
+	return left
+}
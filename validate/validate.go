@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/antonmedv/expr"
+	"github.com/simonalong/gole/config"
 	"github.com/simonalong/gole/constants"
 	"github.com/simonalong/gole/goid"
 	"github.com/simonalong/gole/logger"
@@ -488,6 +489,9 @@ func init() {
 	checkerEntities = append(checkerEntities, CollectorEntity{constants.Condition, matcher.BuildConditionMatcher})
 	checkerEntities = append(checkerEntities, CollectorEntity{constants.Customize, matcher.BuildCustomizeMatcher})
 	checkerEntities = append(checkerEntities, CollectorEntity{constants.Regex, matcher.BuildRegexMatcher})
+
+	/* 配置绑定之后的校验 */
+	config.RegisterBindValidator(Check)
 }
 
 func arraysToString(dataArray []string) string {